@@ -0,0 +1,26 @@
+package domain
+
+// BreakCadence define el ritmo de descansos de un usuario: tras cada
+// pomodoro corresponde un descanso corto, salvo al completar un set de
+// PomodorosPerSet pomodoros, cuando corresponde un descanso largo.
+type BreakCadence struct {
+	ShortBreakMinutes int `json:"short_break_minutes"`
+	LongBreakMinutes  int `json:"long_break_minutes"`
+	PomodorosPerSet   int `json:"pomodoros_per_set"`
+}
+
+// BreakMinutesFor devuelve la duración del descanso que corresponde a la
+// posición indicada dentro del set y si se trata de un descanso largo.
+func (c BreakCadence) BreakMinutesFor(setPosition int) (minutes int, long bool) {
+	if c.PomodorosPerSet > 0 && setPosition >= c.PomodorosPerSet {
+		return c.LongBreakMinutes, true
+	}
+	return c.ShortBreakMinutes, false
+}
+
+// CadenceRepository define la persistencia de la cadencia de cada usuario.
+// GetCadence devuelve (nil, nil) si el usuario no ha configurado ninguna.
+type CadenceRepository interface {
+	GetCadence(userID string) (*BreakCadence, error)
+	SaveCadence(userID string, cadence *BreakCadence) error
+}
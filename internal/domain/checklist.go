@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxChecklistItems limita el tamaño de la lista embebida en la tarea.
+const MaxChecklistItems = 100
+
+// Errores de la lista de verificación de una tarea.
+var (
+	ErrChecklistItemNotFound = errors.New("checklist item not found")
+	ErrChecklistFull         = errors.New("checklist item limit reached")
+	ErrInvalidChecklistOrder = errors.New("checklist order must list every item exactly once")
+)
+
+// ChecklistItem es un paso de una tarea. Se guarda embebido en el
+// documento de la tarea, por lo que se versiona junto con ella.
+type ChecklistItem struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Done      bool       `json:"done"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// AddChecklistItem agrega el ítem al final de la lista.
+func (t *Task) AddChecklistItem(item ChecklistItem) error {
+	if len(t.Checklist) >= MaxChecklistItems {
+		return ErrChecklistFull
+	}
+	t.Checklist = append(t.Checklist, item)
+	return nil
+}
+
+// ToggleChecklistItem invierte el estado del ítem.
+func (t *Task) ToggleChecklistItem(itemID string, at time.Time) error {
+	i := t.checklistIndex(itemID)
+	if i < 0 {
+		return ErrChecklistItemNotFound
+	}
+
+	item := &t.Checklist[i]
+	item.Done = !item.Done
+	if item.Done {
+		item.DoneAt = &at
+	} else {
+		item.DoneAt = nil
+	}
+	return nil
+}
+
+// RemoveChecklistItem quita el ítem conservando el orden del resto.
+func (t *Task) RemoveChecklistItem(itemID string) error {
+	i := t.checklistIndex(itemID)
+	if i < 0 {
+		return ErrChecklistItemNotFound
+	}
+	t.Checklist = append(t.Checklist[:i], t.Checklist[i+1:]...)
+	return nil
+}
+
+// ReorderChecklist reordena la lista según itemIDs, que debe contener
+// cada ítem existente exactamente una vez.
+func (t *Task) ReorderChecklist(itemIDs []string) error {
+	if len(itemIDs) != len(t.Checklist) {
+		return ErrInvalidChecklistOrder
+	}
+
+	byID := make(map[string]ChecklistItem, len(t.Checklist))
+	for _, item := range t.Checklist {
+		byID[item.ID] = item
+	}
+
+	ordered := make([]ChecklistItem, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		item, ok := byID[id]
+		if !ok {
+			return ErrInvalidChecklistOrder
+		}
+		delete(byID, id) // Un ID repetido deja de encontrarse
+		ordered = append(ordered, item)
+	}
+
+	t.Checklist = ordered
+	return nil
+}
+
+// ChecklistDone indica si la tarea tiene ítems y todos están hechos.
+func (t *Task) ChecklistDone() bool {
+	if len(t.Checklist) == 0 {
+		return false
+	}
+	for _, item := range t.Checklist {
+		if !item.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// CompleteIfChecklistDone completa la tarea si tiene AutoComplete y todos
+// sus pasos están hechos. Se aplica dentro de la misma mutación que cambia
+// la lista, para que ambas cosas se persistan en una única escritura.
+func (t *Task) CompleteIfChecklistDone(at time.Time) {
+	if !t.AutoComplete || t.Completed || !t.ChecklistDone() {
+		return
+	}
+	t.Status = TaskStatusCompleted
+	t.Completed = true
+	t.CompletedAt = &at
+}
+
+func (t *Task) checklistIndex(itemID string) int {
+	for i, item := range t.Checklist {
+		if item.ID == itemID {
+			return i
+		}
+	}
+	return -1
+}
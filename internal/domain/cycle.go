@@ -3,9 +3,11 @@ package domain
 import "time"
 
 // PomodoroCycle representa un ciclo completado de un Pomodoro.
-// Es un registro HISTÓRICO que no se modifica una vez guardado.
+// Es un registro HISTÓRICO que no se modifica una vez guardado, salvo
+// BreakUsed, que se marca cuando el descanso posterior se completa.
 type PomodoroCycle struct {
 	ID         string    `json:"id"`
+	SessionID  string    `json:"session_id"`
 	UserID     string    `json:"user_id"`
 	TaskID     string    `json:"task_id"`
 	Duration   int       `json:"duration"` // Duración del ciclo en minutos
@@ -18,4 +20,7 @@ type PomodoroCycle struct {
 type CycleRepository interface {
 	Save(cycle *PomodoroCycle) error
 	GetByTask(taskID string) ([]*PomodoroCycle, error)
+	// GetByUser admite un rango opcional sobre FinishedAt; nil = sin límite.
+	GetByUser(userID string, from, to *time.Time) ([]*PomodoroCycle, error)
+	MarkBreakUsed(sessionID string) error
 }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errores que los repositorios devuelven para que la capa de servicios
+// pueda distinguirlos sin conocer la tecnología de persistencia.
+var (
+	ErrInvalidCursor       = errors.New("invalid pagination cursor")
+	ErrActiveSessionExists = errors.New("user already has an active session")
+	ErrVersionConflict     = errors.New("version conflict")
+	ErrStateConflict       = errors.New("session is no longer in the expected state")
+	ErrPresetNameTaken     = errors.New("user already has a preset with that name")
+	ErrDefaultPresetTaken  = errors.New("user already has a default preset")
+	ErrTagNameTaken        = errors.New("user already has a tag with that name")
+)
+
+// ErrInvalidTransition indica que la máquina de estados no permite la
+// acción desde el estado actual de la sesión.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// ErrInvalidPhaseAdjustment indica que el ajuste pedido dejaría la fase en
+// curso sin duración o con un fin anterior al tiempo ya transcurrido.
+var ErrInvalidPhaseAdjustment = errors.New("phase cannot be shortened beyond its elapsed time")
+
+// VersionConflictError indica que una entidad fue modificada por otra
+// escritura desde que se leyó (control de concurrencia optimista).
+// errors.Is(err, ErrVersionConflict) es verdadero para este tipo.
+type VersionConflictError struct {
+	Entity  string
+	ID      string
+	Version int64 // Versión esperada por quien intentó escribir
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified concurrently (expected version %d)", e.Entity, e.ID, e.Version)
+}
+
+// Is permite comparar contra el sentinel ErrVersionConflict.
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
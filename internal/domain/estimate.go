@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// TaskEstimate compara la estimación de una tarea completada con los
+// pomodoros que realmente consumió. Deviation > 0 indica un desvío por
+// exceso (overrun) y < 0 por defecto (underrun).
+type TaskEstimate struct {
+	TaskID      string    `json:"task_id"`
+	Title       string    `json:"title"`
+	Estimated   int       `json:"estimated"`
+	Actual      int       `json:"actual"`
+	Deviation   int       `json:"deviation"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// NewTaskEstimate construye la comparación de una tarea con estimación.
+// Se compara con los pomodoros propios: los de las subtareas corresponden
+// a sus propias estimaciones.
+func NewTaskEstimate(t *Task) TaskEstimate {
+	e := TaskEstimate{
+		TaskID:    t.ID,
+		Title:     t.Title,
+		Estimated: *t.EstimatedPomodoros,
+		Actual:    t.OwnPomodoros,
+	}
+	e.Deviation = e.Actual - e.Estimated
+	if t.CompletedAt != nil {
+		e.CompletedAt = *t.CompletedAt
+	}
+	return e
+}
+
+// Accuracy devuelve la precisión de la estimación entre 0 y 1: 1 si se
+// acertó exactamente y 0 si el desvío iguala o supera lo estimado.
+func (e TaskEstimate) Accuracy() float64 {
+	if e.Estimated <= 0 {
+		return 0
+	}
+	miss := math.Abs(float64(e.Deviation)) / float64(e.Estimated)
+	return math.Max(0, 1-miss)
+}
+
+// EstimateTrendPoint es la precisión móvil al cierre de una semana local:
+// Tasks y AccuracyPercent consideran las tareas completadas en la ventana
+// de varias semanas que termina en WeekEnd.
+type EstimateTrendPoint struct {
+	WeekStart       time.Time `json:"week_start"`
+	WeekEnd         time.Time `json:"week_end"`
+	Tasks           int       `json:"tasks"`
+	AccuracyPercent int       `json:"accuracy_percent"`
+}
+
+// EstimateAccuracy resume la calidad de las estimaciones de un usuario.
+type EstimateAccuracy struct {
+	UserID   string    `json:"user_id"`
+	Timezone string    `json:"timezone"`
+	From     time.Time `json:"from"`
+
+	TasksEstimated  int     `json:"tasks_estimated"`
+	OnTarget        int     `json:"on_target"`
+	Overrun         int     `json:"overrun"`
+	Underrun        int     `json:"underrun"`
+	AccuracyPercent int     `json:"accuracy_percent"`
+	MeanDeviation   float64 `json:"mean_deviation"` // Pomodoros por tarea, con signo
+
+	TopOverruns  []TaskEstimate       `json:"top_overruns"`
+	TopUnderruns []TaskEstimate       `json:"top_underruns"`
+	Trend        []EstimateTrendPoint `json:"trend"`
+}
+
+// AccuracyPercentOf devuelve la precisión media de las estimaciones en
+// porcentaje entero.
+func AccuracyPercentOf(estimates []TaskEstimate) int {
+	if len(estimates) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, e := range estimates {
+		sum += e.Accuracy()
+	}
+	return int(math.Round(sum / float64(len(estimates)) * 100))
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// FocusHeatmap es una matriz 7x24 de minutos de foco por día de la semana
+// y hora local del usuario. La fila es time.Weekday (0 = domingo) y la
+// columna la hora del día (0-23).
+type FocusHeatmap struct {
+	UserID       string       `json:"user_id"`
+	Timezone     string       `json:"timezone"`
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Minutes      [7][24]int   `json:"minutes"`
+	TotalMinutes int          `json:"total_minutes"`
+	WeekStartDay time.Weekday `json:"week_start_day"` // Para ordenar las filas en el cliente
+}
+
+// Fill reparte el foco de cada intervalo entre las horas locales que
+// abarca. Como las pausas no se registran por hora, los segundos
+// enfocados se distribuyen en proporción al tiempo de reloj de cada hora.
+func (h *FocusHeatmap) Fill(intervals []FocusInterval, loc *time.Location) {
+	var seconds [7][24]float64
+
+	for _, in := range intervals {
+		wall := in.FinishedAt.Sub(in.StartedAt)
+		if wall <= 0 || in.FocusedSeconds <= 0 {
+			continue
+		}
+		ratio := math.Min(1, float64(in.FocusedSeconds)/wall.Seconds())
+
+		end := in.FinishedAt.In(loc)
+		for cursor := in.StartedAt.In(loc); cursor.Before(end); {
+			// Inicio de la hora local siguiente; time.Date normaliza los
+			// saltos de horario de verano.
+			next := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), cursor.Hour()+1, 0, 0, 0, loc)
+			if !next.After(cursor) {
+				next = cursor.Add(time.Hour)
+			}
+			if next.After(end) {
+				next = end
+			}
+
+			seconds[cursor.Weekday()][cursor.Hour()] += next.Sub(cursor).Seconds() * ratio
+			cursor = next
+		}
+	}
+
+	total := 0.0
+	for d := range seconds {
+		for hr := range seconds[d] {
+			h.Minutes[d][hr] = int(math.Round(seconds[d][hr] / 60))
+			total += seconds[d][hr]
+		}
+	}
+	h.TotalMinutes = int(math.Round(total / 60))
+}
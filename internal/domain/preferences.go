@@ -0,0 +1,120 @@
+package domain
+
+import "time"
+
+// GoalMetric indica en qué unidad se mide un objetivo de foco.
+type GoalMetric string
+
+const (
+	GoalMetricPomodoros GoalMetric = "POMODOROS"
+	GoalMetricMinutes   GoalMetric = "MINUTES"
+)
+
+// FocusGoal es un objetivo de foco: Target pomodoros o minutos.
+type FocusGoal struct {
+	Metric GoalMetric `json:"metric"`
+	Target int        `json:"target"`
+}
+
+// Achieved devuelve el avance de los totales en la unidad del objetivo.
+func (g FocusGoal) Achieved(t FocusTotals) int {
+	if g.Metric == GoalMetricMinutes {
+		return t.FocusedSeconds / 60
+	}
+	return t.Pomodoros
+}
+
+// Met indica si los totales alcanzan el objetivo.
+func (g FocusGoal) Met(t FocusTotals) bool {
+	return g.Achieved(t) >= g.Target
+}
+
+// Valores por defecto de las preferencias de un usuario sin configurar.
+const (
+	DefaultTimezone     = "UTC"
+	DefaultLocale       = "es"
+	DefaultWeekStartDay = time.Monday
+)
+
+// UserPreferences agrupa la configuración personal de un usuario. No
+// existe una entidad de usuario: UserID es el identificador externo.
+type UserPreferences struct {
+	UserID   string `json:"user_id"`
+	Timezone string `json:"timezone"` // Zona IANA, p. ej. "America/Bogota"
+	Locale   string `json:"locale"`
+
+	// Duraciones de sesión cuando la petición no las indica ni hay preset
+	DefaultFocusMinutes int `json:"default_focus_minutes"`
+	DefaultBreakMinutes int `json:"default_break_minutes"`
+
+	// Cadencia de descansos largos; nil si no se ha configurado
+	Cadence *BreakCadence `json:"cadence,omitempty"`
+
+	// Iniciar el descanso automáticamente al terminar el focus
+	AutoStartBreak bool `json:"auto_start_break"`
+
+	DailyGoal    *FocusGoal   `json:"daily_goal,omitempty"`
+	WeeklyGoal   *FocusGoal   `json:"weekly_goal,omitempty"`
+	WeekStartDay time.Weekday `json:"week_start_day"` // 0 = domingo
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultUserPreferences devuelve las preferencias de un usuario que aún
+// no ha configurado ninguna.
+func DefaultUserPreferences(userID string) *UserPreferences {
+	return &UserPreferences{
+		UserID:              userID,
+		Timezone:            DefaultTimezone,
+		Locale:              DefaultLocale,
+		DefaultFocusMinutes: DefaultFocusMinutes,
+		DefaultBreakMinutes: DefaultBreakMinutes,
+		WeekStartDay:        DefaultWeekStartDay,
+	}
+}
+
+// Location devuelve la zona horaria del usuario, o UTC si no es válida.
+func (p *UserPreferences) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DayRange devuelve los límites [from, to) del día local del usuario que
+// contiene t.
+func (p *UserPreferences) DayRange(t time.Time) (from, to time.Time) {
+	local := t.In(p.Location())
+	from = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	return from, from.AddDate(0, 0, 1)
+}
+
+// WeekRange devuelve los límites [from, to) de la semana local del usuario
+// que contiene t, empezando en WeekStartDay.
+func (p *UserPreferences) WeekRange(t time.Time) (from, to time.Time) {
+	day, _ := p.DayRange(t)
+	offset := (int(day.Weekday()) - int(p.WeekStartDay) + 7) % 7
+	from = day.AddDate(0, 0, -offset)
+	return from, from.AddDate(0, 0, 7)
+}
+
+// MonthRange devuelve los límites [from, to) del mes local del usuario que
+// contiene t.
+func (p *UserPreferences) MonthRange(t time.Time) (from, to time.Time) {
+	local := t.In(p.Location())
+	from = time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+	return from, from.AddDate(0, 1, 0)
+}
+
+// PreferencesRepository define la persistencia de las preferencias. La
+// cadencia vive en el mismo documento, por eso también la expone.
+// GetPreferences devuelve (nil, nil) si el usuario no tiene ninguna.
+type PreferencesRepository interface {
+	CadenceRepository
+
+	GetPreferences(userID string) (*UserPreferences, error)
+	SavePreferences(prefs *UserPreferences) error
+	// SaveGoals escribe solo los objetivos del usuario; uno nil se elimina.
+	SaveGoals(userID string, daily, weekly *FocusGoal) error
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Duraciones que se aplican cuando ni la petición, ni un preset, ni el
+// usuario definen otras (pomodoro clásico 25/5).
+const (
+	DefaultFocusMinutes = 25
+	DefaultBreakMinutes = 5
+)
+
+// SessionPreset es una plantilla con nombre de duraciones de sesión
+// (p. ej. "Classic 25/5", "Deep work 50/10"). Cada usuario puede marcar
+// como mucho uno como predeterminado.
+type SessionPreset struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	Name         string `json:"name"`
+	FocusMinutes int    `json:"focus_minutes"`
+	BreakMinutes int    `json:"break_minutes"`
+	IsDefault    bool   `json:"is_default"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PresetRepository define la persistencia de los presets de sesión.
+// FindDefault devuelve (nil, nil) si el usuario no tiene predeterminado.
+// Create y Update devuelven ErrPresetNameTaken si el usuario ya tiene otro
+// preset con el mismo nombre, y ErrDefaultPresetTaken si el preset es
+// predeterminado y el usuario ya tiene otro.
+type PresetRepository interface {
+	Create(preset *SessionPreset) error
+	Update(preset *SessionPreset) error
+	Delete(id string) error
+	FindByID(id string) (*SessionPreset, error)
+	FindByUser(userID string) ([]*SessionPreset, error)
+	FindDefault(userID string) (*SessionPreset, error)
+
+	// ClearDefault desmarca el predeterminado del usuario salvo exceptID.
+	ClearDefault(userID, exceptID string) error
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// ReportPeriod es la granularidad de un informe de productividad.
+type ReportPeriod string
+
+const (
+	ReportPeriodDay   ReportPeriod = "day"
+	ReportPeriodWeek  ReportPeriod = "week"
+	ReportPeriodMonth ReportPeriod = "month"
+)
+
+// ProjectBreakdown es el desglose de un informe por proyecto. ProjectID
+// vacío agrupa el trabajo sin proyecto.
+type ProjectBreakdown struct {
+	ProjectID      string `json:"project_id"`
+	FocusMinutes   int    `json:"focus_minutes"`
+	Pomodoros      int    `json:"pomodoros"`
+	CompletedTasks int    `json:"completed_tasks"`
+}
+
+// TagBreakdown es el foco dedicado a tareas con una etiqueta. Una sesión
+// cuenta en todas las etiquetas de su tarea, por lo que la suma de los
+// desgloses puede superar el total del informe.
+type TagBreakdown struct {
+	TagID        string `json:"tag_id"`
+	Name         string `json:"name"`
+	Color        string `json:"color"`
+	FocusMinutes int    `json:"focus_minutes"`
+	Pomodoros    int    `json:"pomodoros"`
+}
+
+// ProductivityReport resume la actividad de un usuario en [From, To).
+type ProductivityReport struct {
+	UserID   string       `json:"user_id"`
+	Period   ReportPeriod `json:"period"`
+	Timezone string       `json:"timezone"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	TagID    string       `json:"tag_id,omitempty"` // Filtro opcional por etiqueta
+
+	FocusMinutes   int `json:"focus_minutes"`
+	Pomodoros      int `json:"pomodoros"`
+	BreaksTaken    int `json:"breaks_taken"`
+	BreaksSkipped  int `json:"breaks_skipped"`
+	Interruptions  int `json:"interruptions"`
+	CompletedTasks int `json:"completed_tasks"`
+
+	Projects []ProjectBreakdown `json:"projects"`
+	Tags     []TagBreakdown     `json:"tags"`
+}
+
+// ReportRepository calcula los informes directamente en la base de datos.
+// BuildReport completa los totales y los desgloses de report a partir de
+// sus campos UserID, From, To y TagID; si TagID no está vacío solo cuenta
+// el trabajo sobre tareas con esa etiqueta. El nombre y el color de
+// TagBreakdown los completa el servicio.
+type ReportRepository interface {
+	BuildReport(report *ProductivityReport) error
+}
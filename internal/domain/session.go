@@ -16,8 +16,112 @@ const (
 	SessionStateBreakRunning  SessionState = "BREAK_RUNNING"
 	SessionStateBreakPaused   SessionState = "BREAK_PAUSED"
 	SessionStateBreakFinished SessionState = "BREAK_FINISHED"
+	SessionStateBreakSkipped  SessionState = "BREAK_SKIPPED"
 )
 
+// Valid indica si el estado es uno de los definidos por el dominio.
+func (s SessionState) Valid() bool {
+	switch s {
+	case SessionStateRunning, SessionStatePaused, SessionStateFinished,
+		SessionStateCancelled, SessionStateBreakRunning,
+		SessionStateBreakPaused, SessionStateBreakFinished,
+		SessionStateBreakSkipped:
+		return true
+	}
+	return false
+}
+
+// ActiveSessionStates son los estados en los que una sesión ocupa al
+// usuario. Cada usuario puede tener a lo sumo una sesión en estos estados.
+var ActiveSessionStates = []SessionState{
+	SessionStateRunning,
+	SessionStatePaused,
+	SessionStateBreakRunning,
+	SessionStateBreakPaused,
+}
+
+// Active indica si el estado es uno de ActiveSessionStates.
+func (s SessionState) Active() bool {
+	for _, st := range ActiveSessionStates {
+		if s == st {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionEventType identifica el tipo de evento registrado en la línea de
+// tiempo de una sesión.
+type SessionEventType string
+
+const (
+	SessionEventStarted       SessionEventType = "STARTED"
+	SessionEventPaused        SessionEventType = "PAUSED"
+	SessionEventResumed       SessionEventType = "RESUMED"
+	SessionEventFinished      SessionEventType = "FINISHED"
+	SessionEventBreakStarted  SessionEventType = "BREAK_STARTED"
+	SessionEventBreakPaused   SessionEventType = "BREAK_PAUSED"
+	SessionEventBreakResumed  SessionEventType = "BREAK_RESUMED"
+	SessionEventBreakFinished SessionEventType = "BREAK_FINISHED"
+	SessionEventBreakSkipped  SessionEventType = "BREAK_SKIPPED"
+	SessionEventCancelled     SessionEventType = "CANCELLED"
+
+	// Fase cerrada por el temporizador del servidor; State indica cuál.
+	SessionEventAutoExpired SessionEventType = "AUTO_EXPIRED"
+
+	// Ajustes de duración de la fase en curso; Minutes indica cuánto.
+	SessionEventExtended  SessionEventType = "EXTENDED"
+	SessionEventShortened SessionEventType = "SHORTENED"
+)
+
+// InterruptionType clasifica una interrupción según la técnica Pomodoro:
+// internas (el propio usuario se distrae) o externas (alguien o algo lo
+// interrumpe).
+type InterruptionType string
+
+const (
+	InterruptionInternal InterruptionType = "INTERNAL"
+	InterruptionExternal InterruptionType = "EXTERNAL"
+)
+
+// Interruption describe el motivo de una pausa del focus.
+type Interruption struct {
+	Type   InterruptionType `json:"type,omitempty"`
+	Reason string           `json:"reason,omitempty"`
+}
+
+// InterruptionCount agrupa las interrupciones por clasificación y motivo.
+// Un Type vacío corresponde a pausas sin clasificar.
+type InterruptionCount struct {
+	Type   InterruptionType `json:"type"`
+	Reason string           `json:"reason"`
+	Count  int              `json:"count"`
+}
+
+// InterruptionFilter acota la consulta de interrupciones. UserID y TaskID
+// son excluyentes; From/To filtran por el instante de la pausa.
+type InterruptionFilter struct {
+	UserID string
+	TaskID string
+	From   *time.Time
+	To     *time.Time
+}
+
+// SessionEvent es una entrada de la línea de tiempo de una sesión. La lista
+// es append-only: los eventos nunca se editan ni se eliminan.
+type SessionEvent struct {
+	Type   SessionEventType `json:"type"`
+	At     time.Time        `json:"at"`
+	State  SessionState     `json:"state"` // Estado resultante tras el evento
+	Reason string           `json:"reason,omitempty"`
+
+	// Solo en eventos PAUSED: clasificación de la interrupción
+	Interruption InterruptionType `json:"interruption,omitempty"`
+
+	// Solo en eventos EXTENDED/SHORTENED: minutos añadidos o quitados
+	Minutes int `json:"minutes,omitempty"`
+}
+
 // Session representa una sesión Pomodoro completa (focus + break).
 type Session struct {
 	ID        string  `json:"id"`
@@ -29,6 +133,11 @@ type Session struct {
 	BreakMinutes int          `json:"break_minutes"`
 	State        SessionState `json:"state"`
 
+	// Posición del pomodoro dentro del set actual (1..PomodorosPerSet) según
+	// la cadencia del usuario; 0 si el usuario no tiene cadencia.
+	SetPosition int  `json:"set_position"`
+	LongBreak   bool `json:"long_break"`
+
 	StartedAt  time.Time  `json:"started_at"`
 	PausedAt   *time.Time `json:"paused_at,omitempty"`
 	FinishedAt *time.Time `json:"finished_at,omitempty"`
@@ -37,15 +146,88 @@ type Session struct {
 	BreakStartedAt  *time.Time `json:"break_started_at,omitempty"`
 	BreakFinishedAt *time.Time `json:"break_finished_at,omitempty"`
 
+	// Deadline de la fase en curso (focus o break). Mientras la sesión está
+	// pausada conserva el valor previo a la pausa y se desplaza al reanudar.
+	PhaseEndsAt *time.Time `json:"phase_ends_at,omitempty"`
+
 	Interruptions int `json:"interruptions"`
 
+	// Contabilidad real del focus: segundos acumulados en pausa durante el
+	// focus y segundos efectivamente enfocados al cerrar la fase.
+	PausedSeconds  int `json:"paused_seconds"`
+	FocusedSeconds int `json:"focused_seconds"`
+
+	// Línea de tiempo de la sesión; se expone en GET /sessions/:id/events
+	// para no inflar cada respuesta.
+	Events []SessionEvent `json:"-"`
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Version se incrementa en cada escritura; TransitionSession solo aplica
+	// si la versión persistida coincide con la leída.
+	Version int64 `json:"version"`
+}
+
+// SessionQuery describe una consulta paginada del historial de sesiones.
+// Los filtros vacíos no se aplican; From/To acotan StartedAt. Cursor es el
+// valor opaco devuelto en SessionPage.NextCursor.
+type SessionQuery struct {
+	UserID    string
+	TaskID    string
+	ProjectID string
+	State     SessionState
+	From      *time.Time
+	To        *time.Time
+
+	Cursor    string
+	Limit     int
+	Ascending bool // Orden por StartedAt; por defecto, más recientes primero
+}
+
+// SessionPage es una página de resultados de SessionQuery.
+type SessionPage struct {
+	Sessions   []*Session `json:"sessions"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+// FocusTotals resume el foco completado en un rango: cuántos pomodoros
+// terminaron (FinishedAt dentro del rango) y cuántos segundos sumaron.
+type FocusTotals struct {
+	Pomodoros      int `json:"pomodoros"`
+	FocusedSeconds int `json:"focused_seconds"`
+}
+
+// FocusInterval es el tramo de reloj de un focus terminado, con los
+// segundos efectivamente enfocados dentro de él (sin pausas).
+type FocusInterval struct {
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	FocusedSeconds int
 }
 
 // SessionRepository define el contrato de persistencia para las sesiones.
+// CreateSession y TransitionSession devuelven ErrActiveSessionExists si la
+// escritura dejaría al usuario con más de una sesión activa.
 type SessionRepository interface {
 	CreateSession(s *Session) error
-	UpdateSession(s *Session) error
+	// TransitionSession persiste la sesión solo si en la base de datos sigue
+	// en el estado from y en la versión leída; si el estado ya cambió
+	// devuelve ErrStateConflict y, si solo cambió la versión,
+	// *VersionConflictError. La comprobación es atómica.
+	TransitionSession(s *Session, from SessionState) error
 	FindByID(id string) (*Session, error)
+	FindByStates(states ...SessionState) ([]*Session, error)
+	// FindLatestByUser devuelve la sesión más reciente del usuario o
+	// (nil, nil) si no tiene ninguna.
+	FindLatestByUser(userID string) (*Session, error)
+	// FindActiveByUser devuelve la sesión activa del usuario o (nil, nil).
+	FindActiveByUser(userID string) (*Session, error)
+	FindSessions(query SessionQuery) (*SessionPage, error)
+	CountInterruptions(filter InterruptionFilter) ([]InterruptionCount, error)
+	// SumFocus totaliza los focus del usuario terminados en [from, to).
+	SumFocus(userID string, from, to time.Time) (*FocusTotals, error)
+	// FindFocusIntervals devuelve los focus del usuario terminados en
+	// [from, to), sin cargar el resto del documento.
+	FindFocusIntervals(userID string, from, to time.Time) ([]FocusInterval, error)
 }
@@ -0,0 +1,392 @@
+package domain
+
+import "time"
+
+// SessionAction identifica una acción que hace avanzar el ciclo de vida
+// de una sesión.
+type SessionAction string
+
+const (
+	SessionActionPause       SessionAction = "pause"
+	SessionActionResume      SessionAction = "resume"
+	SessionActionFinish      SessionAction = "finish"
+	SessionActionCancel      SessionAction = "cancel"
+	SessionActionStartBreak  SessionAction = "start_break"
+	SessionActionPauseBreak  SessionAction = "pause_break"
+	SessionActionResumeBreak SessionAction = "resume_break"
+	SessionActionFinishBreak SessionAction = "finish_break"
+	SessionActionSkipBreak   SessionAction = "skip_break"
+
+	// Ajustan la duración de la fase en curso sin cambiar de estado
+	SessionActionExtend  SessionAction = "extend"
+	SessionActionShorten SessionAction = "shorten"
+
+	// Acciones internas del temporizador del servidor
+	SessionActionExpireFocus SessionAction = "expire_focus"
+	SessionActionExpireBreak SessionAction = "expire_break"
+)
+
+// SessionTransition es una fila de la tabla de transiciones: desde qué
+// estados se permite la acción, a qué estado lleva, qué evento registra en
+// la línea de tiempo y qué efecto aplica sobre la sesión. Un To vacío
+// indica que la acción no cambia el estado.
+type SessionTransition struct {
+	Action SessionAction  `json:"action"`
+	From   []SessionState `json:"-"`
+	To     SessionState   `json:"to,omitempty"`
+
+	Event    SessionEventType               `json:"-"`
+	Effect   func(s *Session, at time.Time) `json:"-"`
+	Internal bool                           `json:"-"` // No disponible para clientes
+}
+
+// allows indica si la transición admite el estado indicado como origen.
+func (t SessionTransition) allows(state SessionState) bool {
+	for _, st := range t.From {
+		if st == state {
+			return true
+		}
+	}
+	return false
+}
+
+// SessionHook se ejecuta después de que una transición se haya persistido.
+// from es el estado previo de la sesión.
+type SessionHook func(session *Session, from SessionState)
+
+// SessionStateMachine concentra las reglas del ciclo de vida de una sesión
+// en una tabla declarativa, más los hooks registrados por los servicios.
+type SessionStateMachine struct {
+	transitions []SessionTransition
+	hooks       map[SessionAction][]SessionHook
+}
+
+// NewSessionStateMachine construye la máquina de estados con la tabla de
+// transiciones del dominio.
+func NewSessionStateMachine() *SessionStateMachine {
+	return &SessionStateMachine{
+		transitions: sessionTransitions(),
+		hooks:       make(map[SessionAction][]SessionHook),
+	}
+}
+
+// sessionTransitions define el ciclo de vida completo:
+//
+//	RUNNING ⇄ PAUSED → FINISHED → BREAK_RUNNING ⇄ BREAK_PAUSED → BREAK_FINISHED
+//	                      FINISHED → BREAK_SKIPPED
+//
+// Cualquier estado activo puede terminar en CANCELLED o ajustar la
+// duración de su fase (extend/shorten).
+func sessionTransitions() []SessionTransition {
+	focus := []SessionState{SessionStateRunning, SessionStatePaused}
+	breaks := []SessionState{SessionStateBreakRunning, SessionStateBreakPaused}
+	active := append(append([]SessionState{}, focus...), breaks...)
+
+	return []SessionTransition{
+		{
+			Action: SessionActionPause,
+			From:   []SessionState{SessionStateRunning},
+			To:     SessionStatePaused,
+			Event:  SessionEventPaused,
+			Effect: func(s *Session, at time.Time) {
+				s.PausedAt = &at
+				s.Interruptions++
+			},
+		},
+		{
+			Action: SessionActionResume,
+			From:   []SessionState{SessionStatePaused},
+			To:     SessionStateRunning,
+			Event:  SessionEventResumed,
+			Effect: func(s *Session, at time.Time) {
+				s.shiftDeadline(at)
+				s.accumulatePause(at)
+			},
+		},
+		{
+			Action: SessionActionFinish,
+			From:   focus,
+			To:     SessionStateFinished,
+			Event:  SessionEventFinished,
+			Effect: completeFocus,
+		},
+		{
+			Action:   SessionActionExpireFocus,
+			From:     []SessionState{SessionStateRunning},
+			To:       SessionStateFinished,
+			Event:    SessionEventAutoExpired,
+			Effect:   completeFocus,
+			Internal: true,
+		},
+		{
+			Action: SessionActionStartBreak,
+			From:   []SessionState{SessionStateFinished},
+			To:     SessionStateBreakRunning,
+			Event:  SessionEventBreakStarted,
+			Effect: func(s *Session, at time.Time) {
+				endsAt := at.Add(time.Duration(s.BreakMinutes) * time.Minute)
+				s.BreakStartedAt = &at
+				s.PhaseEndsAt = &endsAt
+			},
+		},
+		{
+			// El ciclo conserva BreakUsed=false: el descanso no se tomó.
+			Action: SessionActionSkipBreak,
+			From:   []SessionState{SessionStateFinished},
+			To:     SessionStateBreakSkipped,
+			Event:  SessionEventBreakSkipped,
+		},
+		{
+			Action: SessionActionPauseBreak,
+			From:   []SessionState{SessionStateBreakRunning},
+			To:     SessionStateBreakPaused,
+			Event:  SessionEventBreakPaused,
+			Effect: func(s *Session, at time.Time) {
+				s.PausedAt = &at
+			},
+		},
+		{
+			Action: SessionActionResumeBreak,
+			From:   []SessionState{SessionStateBreakPaused},
+			To:     SessionStateBreakRunning,
+			Event:  SessionEventBreakResumed,
+			Effect: func(s *Session, at time.Time) {
+				s.shiftDeadline(at)
+				s.PausedAt = nil
+			},
+		},
+		{
+			Action: SessionActionFinishBreak,
+			From:   breaks,
+			To:     SessionStateBreakFinished,
+			Event:  SessionEventBreakFinished,
+			Effect: completeBreak,
+		},
+		{
+			Action:   SessionActionExpireBreak,
+			From:     []SessionState{SessionStateBreakRunning},
+			To:       SessionStateBreakFinished,
+			Event:    SessionEventAutoExpired,
+			Effect:   completeBreak,
+			Internal: true,
+		},
+		{
+			Action: SessionActionCancel,
+			From:   active,
+			To:     SessionStateCancelled,
+			Event:  SessionEventCancelled,
+			Effect: func(s *Session, at time.Time) {
+				if s.State == SessionStateRunning || s.State == SessionStatePaused {
+					s.settleFocusTime(at)
+				}
+				s.PausedAt = nil
+				s.PhaseEndsAt = nil
+			},
+		},
+		// El ajuste en sí depende de los minutos pedidos: lo aplica
+		// Session.AdjustPhase antes de ejecutar la acción.
+		{
+			Action: SessionActionExtend,
+			From:   active,
+			Event:  SessionEventExtended,
+		},
+		{
+			Action: SessionActionShorten,
+			From:   active,
+			Event:  SessionEventShortened,
+		},
+	}
+}
+
+// Transition devuelve la fila de la tabla asociada a la acción.
+func (m *SessionStateMachine) Transition(action SessionAction) (SessionTransition, bool) {
+	for _, t := range m.transitions {
+		if t.Action == action {
+			return t, true
+		}
+	}
+	return SessionTransition{}, false
+}
+
+// Can indica si la acción está permitida desde el estado indicado.
+func (m *SessionStateMachine) Can(state SessionState, action SessionAction) bool {
+	t, ok := m.Transition(action)
+	return ok && t.allows(state)
+}
+
+// Available lista las transiciones que un cliente puede ejecutar desde el
+// estado indicado, excluyendo las acciones internas.
+func (m *SessionStateMachine) Available(state SessionState) []SessionTransition {
+	available := []SessionTransition{}
+	for _, t := range m.transitions {
+		if !t.Internal && t.allows(state) {
+			available = append(available, t)
+		}
+	}
+	return available
+}
+
+// Apply ejecuta la acción sobre la sesión en memoria: valida el estado de
+// origen, aplica el efecto, cambia el estado y registra el evento en la
+// línea de tiempo, que se devuelve para que el llamador pueda anotarlo.
+// No persiste ni ejecuta hooks.
+func (m *SessionStateMachine) Apply(s *Session, action SessionAction, at time.Time) (*SessionEvent, error) {
+	t, ok := m.Transition(action)
+	if !ok || !t.allows(s.State) {
+		return nil, ErrInvalidTransition
+	}
+
+	if t.Effect != nil {
+		t.Effect(s, at)
+	}
+	if t.To != "" {
+		s.State = t.To
+	}
+	s.UpdatedAt = time.Now()
+
+	return s.RecordEvent(t.Event, at, ""), nil
+}
+
+// OnTransition registra un hook que se ejecutará tras persistir la acción.
+func (m *SessionStateMachine) OnTransition(action SessionAction, hook SessionHook) {
+	m.hooks[action] = append(m.hooks[action], hook)
+}
+
+// RunHooks ejecuta, en orden de registro, los hooks de la acción.
+func (m *SessionStateMachine) RunHooks(action SessionAction, s *Session, from SessionState) {
+	for _, hook := range m.hooks[action] {
+		hook(s, from)
+	}
+}
+
+// RecordEvent agrega un evento a la línea de tiempo con el estado actual
+// de la sesión como estado resultante y devuelve el evento agregado.
+func (s *Session) RecordEvent(eventType SessionEventType, at time.Time, reason string) *SessionEvent {
+	s.Events = append(s.Events, SessionEvent{
+		Type:   eventType,
+		At:     at,
+		State:  s.State,
+		Reason: reason,
+	})
+	return &s.Events[len(s.Events)-1]
+}
+
+// AdjustPhase alarga (delta > 0) o acorta (delta < 0) en minutos la fase
+// en curso, moviendo su deadline. Como el focus acreditado se acota a
+// FocusMinutes, el ajuste también se refleja en las métricas de la tarea.
+//
+// La fase no puede quedar por debajo de un minuto ni terminar antes del
+// instante de referencia: at, o el inicio de la pausa si está pausada.
+func (s *Session) AdjustPhase(delta int, at time.Time) error {
+	minutes := &s.FocusMinutes
+	if s.State == SessionStateBreakRunning || s.State == SessionStateBreakPaused {
+		minutes = &s.BreakMinutes
+	}
+
+	if *minutes+delta < 1 {
+		return ErrInvalidPhaseAdjustment
+	}
+
+	if s.EnsurePhaseDeadline() != nil {
+		endsAt := s.PhaseEndsAt.Add(time.Duration(delta) * time.Minute)
+
+		ref := at
+		if s.PausedAt != nil {
+			ref = *s.PausedAt
+		}
+		if endsAt.Before(ref) {
+			return ErrInvalidPhaseAdjustment
+		}
+
+		s.PhaseEndsAt = &endsAt
+	}
+
+	*minutes += delta
+	return nil
+}
+
+// EnsurePhaseDeadline devuelve el deadline de la fase en curso, o nil si
+// la sesión no tiene una fase activa. Las sesiones guardadas antes de que
+// existiera PhaseEndsAt no lo tienen: se deriva de StartedAt + FocusMinutes
+// + PausedSeconds en el focus, o de BreakStartedAt + BreakMinutes en el
+// break (las pausas de break no se contabilizaban), y queda asignado.
+func (s *Session) EnsurePhaseDeadline() *time.Time {
+	if s.PhaseEndsAt != nil {
+		return s.PhaseEndsAt
+	}
+
+	var endsAt time.Time
+	switch s.State {
+	case SessionStateRunning, SessionStatePaused:
+		endsAt = s.StartedAt.
+			Add(time.Duration(s.FocusMinutes) * time.Minute).
+			Add(time.Duration(s.PausedSeconds) * time.Second)
+	case SessionStateBreakRunning, SessionStateBreakPaused:
+		if s.BreakStartedAt == nil {
+			return nil
+		}
+		endsAt = s.BreakStartedAt.Add(time.Duration(s.BreakMinutes) * time.Minute)
+	default:
+		return nil
+	}
+
+	s.PhaseEndsAt = &endsAt
+	return s.PhaseEndsAt
+}
+
+// completeFocus cierra la fase de focus en el instante indicado.
+func completeFocus(s *Session, at time.Time) {
+	s.settleFocusTime(at)
+	s.FinishedAt = &at
+	s.PhaseEndsAt = nil
+}
+
+// completeBreak cierra la fase de descanso en el instante indicado.
+func completeBreak(s *Session, at time.Time) {
+	s.BreakFinishedAt = &at
+	s.PausedAt = nil
+	s.PhaseEndsAt = nil
+}
+
+// shiftDeadline desplaza el deadline de la fase actual tanto tiempo como
+// la sesión estuvo pausada, de modo que la pausa no consuma la fase.
+func (s *Session) shiftDeadline(now time.Time) {
+	if s.EnsurePhaseDeadline() == nil || s.PausedAt == nil {
+		return
+	}
+
+	endsAt := s.PhaseEndsAt.Add(now.Sub(*s.PausedAt))
+	s.PhaseEndsAt = &endsAt
+}
+
+// accumulatePause suma al acumulado de la sesión el tiempo transcurrido
+// desde PausedAt y limpia la marca de pausa. Solo aplica a pausas del focus.
+func (s *Session) accumulatePause(now time.Time) {
+	if s.PausedAt == nil {
+		return
+	}
+
+	if paused := now.Sub(*s.PausedAt); paused > 0 {
+		s.PausedSeconds += int(paused / time.Second)
+	}
+	s.PausedAt = nil
+}
+
+// settleFocusTime cierra la contabilidad del focus en el instante indicado:
+// liquida una pausa abierta y calcula los segundos efectivamente enfocados,
+// acotados a la duración planificada de la fase.
+func (s *Session) settleFocusTime(at time.Time) {
+	if s.State == SessionStatePaused {
+		s.accumulatePause(at)
+	}
+
+	focused := int(at.Sub(s.StartedAt)/time.Second) - s.PausedSeconds
+	if planned := s.FocusMinutes * 60; focused > planned {
+		focused = planned
+	}
+	if focused < 0 {
+		focused = 0
+	}
+
+	s.FocusedSeconds = focused
+}
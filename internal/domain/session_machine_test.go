@@ -0,0 +1,557 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var (
+	allSessionStates = []SessionState{
+		SessionStateRunning,
+		SessionStatePaused,
+		SessionStateFinished,
+		SessionStateCancelled,
+		SessionStateBreakRunning,
+		SessionStateBreakPaused,
+		SessionStateBreakFinished,
+		SessionStateBreakSkipped,
+	}
+
+	activeStates = []SessionState{
+		SessionStateRunning,
+		SessionStatePaused,
+		SessionStateBreakRunning,
+		SessionStateBreakPaused,
+	}
+)
+
+// expectedTransition describe, con independencia de sessionTransitions, lo
+// que cada acción debe permitir. Un To vacío significa que no cambia el
+// estado.
+type expectedTransition struct {
+	from     []SessionState
+	to       SessionState
+	event    SessionEventType
+	internal bool
+}
+
+var expectedTransitions = map[SessionAction]expectedTransition{
+	SessionActionPause: {
+		from:  []SessionState{SessionStateRunning},
+		to:    SessionStatePaused,
+		event: SessionEventPaused,
+	},
+	SessionActionResume: {
+		from:  []SessionState{SessionStatePaused},
+		to:    SessionStateRunning,
+		event: SessionEventResumed,
+	},
+	SessionActionFinish: {
+		from:  []SessionState{SessionStateRunning, SessionStatePaused},
+		to:    SessionStateFinished,
+		event: SessionEventFinished,
+	},
+	SessionActionExpireFocus: {
+		from:     []SessionState{SessionStateRunning},
+		to:       SessionStateFinished,
+		event:    SessionEventAutoExpired,
+		internal: true,
+	},
+	SessionActionStartBreak: {
+		from:  []SessionState{SessionStateFinished},
+		to:    SessionStateBreakRunning,
+		event: SessionEventBreakStarted,
+	},
+	SessionActionSkipBreak: {
+		from:  []SessionState{SessionStateFinished},
+		to:    SessionStateBreakSkipped,
+		event: SessionEventBreakSkipped,
+	},
+	SessionActionPauseBreak: {
+		from:  []SessionState{SessionStateBreakRunning},
+		to:    SessionStateBreakPaused,
+		event: SessionEventBreakPaused,
+	},
+	SessionActionResumeBreak: {
+		from:  []SessionState{SessionStateBreakPaused},
+		to:    SessionStateBreakRunning,
+		event: SessionEventBreakResumed,
+	},
+	SessionActionFinishBreak: {
+		from:  []SessionState{SessionStateBreakRunning, SessionStateBreakPaused},
+		to:    SessionStateBreakFinished,
+		event: SessionEventBreakFinished,
+	},
+	SessionActionExpireBreak: {
+		from:     []SessionState{SessionStateBreakRunning},
+		to:       SessionStateBreakFinished,
+		event:    SessionEventAutoExpired,
+		internal: true,
+	},
+	SessionActionCancel: {
+		from:  activeStates,
+		to:    SessionStateCancelled,
+		event: SessionEventCancelled,
+	},
+	SessionActionExtend: {
+		from:  activeStates,
+		event: SessionEventExtended,
+	},
+	SessionActionShorten: {
+		from:  activeStates,
+		event: SessionEventShortened,
+	},
+}
+
+var testStart = time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// sessionIn construye una sesión coherente con el estado indicado: la
+// fase de focus empezó en testStart y, si corresponde, la pausa a los 5
+// minutos y el break a los 25.
+func sessionIn(state SessionState) *Session {
+	s := &Session{
+		ID:           "s1",
+		UserID:       "u1",
+		FocusMinutes: 25,
+		BreakMinutes: 5,
+		State:        state,
+		StartedAt:    testStart,
+		Version:      1,
+	}
+
+	pausedAt := testStart.Add(5 * time.Minute)
+	focusEnds := testStart.Add(25 * time.Minute)
+	breakStart := focusEnds
+	breakEnds := breakStart.Add(5 * time.Minute)
+	breakPausedAt := breakStart.Add(2 * time.Minute)
+
+	switch state {
+	case SessionStateRunning:
+		s.PhaseEndsAt = &focusEnds
+	case SessionStatePaused:
+		s.PhaseEndsAt = &focusEnds
+		s.PausedAt = &pausedAt
+		s.Interruptions = 1
+	case SessionStateFinished, SessionStateBreakSkipped:
+		s.FinishedAt = &focusEnds
+		s.FocusedSeconds = 25 * 60
+	case SessionStateCancelled:
+		s.FocusedSeconds = 60
+	case SessionStateBreakRunning:
+		s.FinishedAt = &focusEnds
+		s.BreakStartedAt = &breakStart
+		s.PhaseEndsAt = &breakEnds
+	case SessionStateBreakPaused:
+		s.FinishedAt = &focusEnds
+		s.BreakStartedAt = &breakStart
+		s.PhaseEndsAt = &breakEnds
+		s.PausedAt = &breakPausedAt
+	case SessionStateBreakFinished:
+		s.FinishedAt = &focusEnds
+		s.BreakStartedAt = &breakStart
+		s.BreakFinishedAt = &breakEnds
+	}
+	return s
+}
+
+func containsState(states []SessionState, state SessionState) bool {
+	for _, st := range states {
+		if st == state {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSessionStateMachineTable(t *testing.T) {
+	m := NewSessionStateMachine()
+
+	for action := range expectedTransitions {
+		if _, ok := m.Transition(action); !ok {
+			t.Errorf("la acción %s no está en la tabla", action)
+		}
+	}
+	for _, tr := range m.transitions {
+		if _, ok := expectedTransitions[tr.Action]; !ok {
+			t.Errorf("la acción %s no está cubierta por el test", tr.Action)
+		}
+	}
+
+	at := testStart.Add(12 * time.Minute)
+
+	for _, state := range allSessionStates {
+		available := map[SessionAction]bool{}
+		for _, tr := range m.Available(state) {
+			available[tr.Action] = true
+		}
+
+		for action, want := range expectedTransitions {
+			allowed := containsState(want.from, state)
+
+			t.Run(string(state)+"/"+string(action), func(t *testing.T) {
+				if got := m.Can(state, action); got != allowed {
+					t.Fatalf("Can = %v, want %v", got, allowed)
+				}
+				if got, want := available[action], allowed && !want.internal; got != want {
+					t.Errorf("Available incluye la acción = %v, want %v", got, want)
+				}
+
+				s := sessionIn(state)
+				event, err := m.Apply(s, action, at)
+
+				if !allowed {
+					if !errors.Is(err, ErrInvalidTransition) {
+						t.Fatalf("Apply err = %v, want ErrInvalidTransition", err)
+					}
+					if s.State != state || len(s.Events) != 0 {
+						t.Fatalf("una transición rechazada modificó la sesión: state %s, %d eventos", s.State, len(s.Events))
+					}
+					return
+				}
+
+				if err != nil {
+					t.Fatalf("Apply: %v", err)
+				}
+
+				wantState := want.to
+				if wantState == "" {
+					wantState = state
+				}
+				if s.State != wantState {
+					t.Errorf("state = %s, want %s", s.State, wantState)
+				}
+				if tr, _ := m.Transition(action); tr.To != want.to {
+					t.Errorf("To = %q, want %q", tr.To, want.to)
+				}
+
+				if len(s.Events) != 1 {
+					t.Fatalf("eventos = %d, want 1", len(s.Events))
+				}
+				if event != &s.Events[0] {
+					t.Error("Apply no devolvió el evento registrado")
+				}
+				if event.Type != want.event || event.State != wantState || !event.At.Equal(at) {
+					t.Errorf("evento = %+v, want %s en %s a las %s", *event, want.event, wantState, at)
+				}
+			})
+		}
+	}
+}
+
+func TestSessionTransitionEffects(t *testing.T) {
+	m := NewSessionStateMachine()
+	minute := time.Minute
+
+	apply := func(t *testing.T, s *Session, action SessionAction, at time.Time) {
+		t.Helper()
+		if _, err := m.Apply(s, action, at); err != nil {
+			t.Fatalf("Apply(%s): %v", action, err)
+		}
+	}
+
+	t.Run("pause marks the interruption", func(t *testing.T) {
+		s := sessionIn(SessionStateRunning)
+		at := testStart.Add(3 * minute)
+		apply(t, s, SessionActionPause, at)
+
+		if s.PausedAt == nil || !s.PausedAt.Equal(at) {
+			t.Errorf("PausedAt = %v, want %s", s.PausedAt, at)
+		}
+		if s.Interruptions != 1 {
+			t.Errorf("Interruptions = %d, want 1", s.Interruptions)
+		}
+	})
+
+	t.Run("resume accumulates the pause and shifts the deadline", func(t *testing.T) {
+		s := sessionIn(SessionStatePaused) // En pausa desde el minuto 5
+		s.PausedSeconds = 30
+		apply(t, s, SessionActionResume, testStart.Add(7*minute))
+
+		if s.PausedAt != nil {
+			t.Errorf("PausedAt = %v, want nil", s.PausedAt)
+		}
+		if s.PausedSeconds != 30+120 {
+			t.Errorf("PausedSeconds = %d, want 150", s.PausedSeconds)
+		}
+		if want := testStart.Add(27 * minute); !s.PhaseEndsAt.Equal(want) {
+			t.Errorf("PhaseEndsAt = %s, want %s", s.PhaseEndsAt, want)
+		}
+	})
+
+	t.Run("resume derives a missing deadline before shifting it", func(t *testing.T) {
+		s := sessionIn(SessionStatePaused)
+		s.PhaseEndsAt = nil
+		s.PausedSeconds = 60
+		apply(t, s, SessionActionResume, testStart.Add(7*minute))
+
+		// 25 min + 1 min de pausas previas + 2 min de esta pausa
+		if want := testStart.Add(28 * minute); s.PhaseEndsAt == nil || !s.PhaseEndsAt.Equal(want) {
+			t.Errorf("PhaseEndsAt = %v, want %s", s.PhaseEndsAt, want)
+		}
+	})
+
+	t.Run("finish caps the focused time at the planned phase", func(t *testing.T) {
+		s := sessionIn(SessionStateRunning)
+		at := testStart.Add(40 * minute)
+		apply(t, s, SessionActionFinish, at)
+
+		if s.FocusedSeconds != 25*60 {
+			t.Errorf("FocusedSeconds = %d, want %d", s.FocusedSeconds, 25*60)
+		}
+		if s.FinishedAt == nil || !s.FinishedAt.Equal(at) {
+			t.Errorf("FinishedAt = %v, want %s", s.FinishedAt, at)
+		}
+		if s.PhaseEndsAt != nil {
+			t.Errorf("PhaseEndsAt = %v, want nil", s.PhaseEndsAt)
+		}
+	})
+
+	t.Run("finish subtracts accumulated pauses", func(t *testing.T) {
+		s := sessionIn(SessionStateRunning)
+		s.PausedSeconds = 90
+		apply(t, s, SessionActionFinish, testStart.Add(10*minute))
+
+		if s.FocusedSeconds != 600-90 {
+			t.Errorf("FocusedSeconds = %d, want 510", s.FocusedSeconds)
+		}
+	})
+
+	t.Run("finish while paused settles the open pause", func(t *testing.T) {
+		s := sessionIn(SessionStatePaused) // En pausa desde el minuto 5
+		s.PausedSeconds = 30
+		apply(t, s, SessionActionFinish, testStart.Add(15*minute))
+
+		if s.PausedAt != nil {
+			t.Errorf("PausedAt = %v, want nil", s.PausedAt)
+		}
+		if s.PausedSeconds != 30+600 {
+			t.Errorf("PausedSeconds = %d, want 630", s.PausedSeconds)
+		}
+		if s.FocusedSeconds != 900-630 {
+			t.Errorf("FocusedSeconds = %d, want 270", s.FocusedSeconds)
+		}
+	})
+
+	t.Run("expire focus completes like finish", func(t *testing.T) {
+		s := sessionIn(SessionStateRunning)
+		at := testStart.Add(25 * minute)
+		apply(t, s, SessionActionExpireFocus, at)
+
+		if s.FocusedSeconds != 25*60 || s.FinishedAt == nil || s.PhaseEndsAt != nil {
+			t.Errorf("focus no cerrado: FocusedSeconds %d, FinishedAt %v, PhaseEndsAt %v", s.FocusedSeconds, s.FinishedAt, s.PhaseEndsAt)
+		}
+	})
+
+	t.Run("cancel during focus settles the focused time", func(t *testing.T) {
+		s := sessionIn(SessionStatePaused)
+		apply(t, s, SessionActionCancel, testStart.Add(8*minute))
+
+		if s.FocusedSeconds != 5*60 {
+			t.Errorf("FocusedSeconds = %d, want 300", s.FocusedSeconds)
+		}
+		if s.PausedAt != nil || s.PhaseEndsAt != nil {
+			t.Errorf("PausedAt %v, PhaseEndsAt %v, want nil", s.PausedAt, s.PhaseEndsAt)
+		}
+	})
+
+	t.Run("cancel during break keeps the focused time", func(t *testing.T) {
+		s := sessionIn(SessionStateBreakPaused)
+		s.FocusedSeconds = 25 * 60
+		apply(t, s, SessionActionCancel, testStart.Add(40*minute))
+
+		if s.FocusedSeconds != 25*60 {
+			t.Errorf("FocusedSeconds = %d, want %d", s.FocusedSeconds, 25*60)
+		}
+		if s.PausedAt != nil || s.PhaseEndsAt != nil {
+			t.Errorf("PausedAt %v, PhaseEndsAt %v, want nil", s.PausedAt, s.PhaseEndsAt)
+		}
+	})
+
+	t.Run("start break sets the break deadline", func(t *testing.T) {
+		s := sessionIn(SessionStateFinished)
+		at := testStart.Add(26 * minute)
+		apply(t, s, SessionActionStartBreak, at)
+
+		if s.BreakStartedAt == nil || !s.BreakStartedAt.Equal(at) {
+			t.Errorf("BreakStartedAt = %v, want %s", s.BreakStartedAt, at)
+		}
+		if want := at.Add(5 * minute); s.PhaseEndsAt == nil || !s.PhaseEndsAt.Equal(want) {
+			t.Errorf("PhaseEndsAt = %v, want %s", s.PhaseEndsAt, want)
+		}
+	})
+
+	t.Run("resume break shifts the deadline without counting focus pauses", func(t *testing.T) {
+		s := sessionIn(SessionStateBreakPaused) // En pausa desde el minuto 27
+		apply(t, s, SessionActionResumeBreak, testStart.Add(30*minute))
+
+		if s.PausedAt != nil {
+			t.Errorf("PausedAt = %v, want nil", s.PausedAt)
+		}
+		if s.PausedSeconds != 0 {
+			t.Errorf("PausedSeconds = %d, want 0", s.PausedSeconds)
+		}
+		if want := testStart.Add(33 * minute); !s.PhaseEndsAt.Equal(want) {
+			t.Errorf("PhaseEndsAt = %s, want %s", s.PhaseEndsAt, want)
+		}
+	})
+
+	t.Run("finish break closes the phase", func(t *testing.T) {
+		s := sessionIn(SessionStateBreakPaused)
+		at := testStart.Add(29 * minute)
+		apply(t, s, SessionActionFinishBreak, at)
+
+		if s.BreakFinishedAt == nil || !s.BreakFinishedAt.Equal(at) {
+			t.Errorf("BreakFinishedAt = %v, want %s", s.BreakFinishedAt, at)
+		}
+		if s.PausedAt != nil || s.PhaseEndsAt != nil {
+			t.Errorf("PausedAt %v, PhaseEndsAt %v, want nil", s.PausedAt, s.PhaseEndsAt)
+		}
+	})
+}
+
+func TestAdjustPhase(t *testing.T) {
+	minute := time.Minute
+
+	cases := []struct {
+		name    string
+		state   SessionState
+		delta   int
+		at      time.Time
+		wantErr bool
+		// Minutos y deadline esperados de la fase en curso
+		wantMinutes int
+		wantEndsAt  time.Time
+	}{
+		{
+			name: "extend focus", state: SessionStateRunning,
+			delta: 5, at: testStart.Add(10 * minute),
+			wantMinutes: 30, wantEndsAt: testStart.Add(30 * minute),
+		},
+		{
+			name: "shorten focus", state: SessionStateRunning,
+			delta: -10, at: testStart.Add(10 * minute),
+			wantMinutes: 15, wantEndsAt: testStart.Add(15 * minute),
+		},
+		{
+			name: "shorten focus to the current instant", state: SessionStateRunning,
+			delta: -15, at: testStart.Add(10 * minute),
+			wantMinutes: 10, wantEndsAt: testStart.Add(10 * minute),
+		},
+		{
+			name: "shorten focus before the current instant", state: SessionStateRunning,
+			delta: -16, at: testStart.Add(10 * minute),
+			wantErr: true,
+		},
+		{
+			name: "shorten focus below one minute", state: SessionStateRunning,
+			delta: -25, at: testStart,
+			wantErr: true,
+		},
+		{
+			// La referencia es el inicio de la pausa (minuto 5), no at
+			name: "shorten paused focus up to the pause", state: SessionStatePaused,
+			delta: -20, at: testStart.Add(12 * minute),
+			wantMinutes: 5, wantEndsAt: testStart.Add(5 * minute),
+		},
+		{
+			name: "shorten paused focus before the pause", state: SessionStatePaused,
+			delta: -21, at: testStart.Add(12 * minute),
+			wantErr: true,
+		},
+		{
+			name: "extend break", state: SessionStateBreakRunning,
+			delta: 3, at: testStart.Add(26 * minute),
+			wantMinutes: 8, wantEndsAt: testStart.Add(33 * minute),
+		},
+		{
+			name: "shorten break below one minute", state: SessionStateBreakPaused,
+			delta: -5, at: testStart.Add(26 * minute),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := sessionIn(tc.state)
+			before := *s
+
+			err := s.AdjustPhase(tc.delta, tc.at)
+
+			minutes := s.FocusMinutes
+			if tc.state == SessionStateBreakRunning || tc.state == SessionStateBreakPaused {
+				minutes = s.BreakMinutes
+			}
+
+			if tc.wantErr {
+				if !errors.Is(err, ErrInvalidPhaseAdjustment) {
+					t.Fatalf("err = %v, want ErrInvalidPhaseAdjustment", err)
+				}
+				if s.FocusMinutes != before.FocusMinutes || s.BreakMinutes != before.BreakMinutes ||
+					!s.PhaseEndsAt.Equal(*before.PhaseEndsAt) {
+					t.Fatal("un ajuste rechazado modificó la sesión")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("AdjustPhase: %v", err)
+			}
+			if minutes != tc.wantMinutes {
+				t.Errorf("minutos = %d, want %d", minutes, tc.wantMinutes)
+			}
+			if !s.PhaseEndsAt.Equal(tc.wantEndsAt) {
+				t.Errorf("PhaseEndsAt = %s, want %s", s.PhaseEndsAt, tc.wantEndsAt)
+			}
+		})
+	}
+}
+
+func TestEnsurePhaseDeadline(t *testing.T) {
+	minute := time.Minute
+
+	cases := []struct {
+		state         SessionState
+		pausedSeconds int
+		want          *time.Time
+	}{
+		{state: SessionStateRunning, want: ptrTime(testStart.Add(25 * minute))},
+		{state: SessionStatePaused, pausedSeconds: 120, want: ptrTime(testStart.Add(27 * minute))},
+		{state: SessionStateBreakRunning, want: ptrTime(testStart.Add(30 * minute))},
+		{state: SessionStateBreakPaused, want: ptrTime(testStart.Add(30 * minute))},
+		{state: SessionStateFinished},
+		{state: SessionStateCancelled},
+		{state: SessionStateBreakFinished},
+		{state: SessionStateBreakSkipped},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.state), func(t *testing.T) {
+			s := sessionIn(tc.state)
+			s.PhaseEndsAt = nil // Sesión guardada antes de PhaseEndsAt
+			s.PausedSeconds = tc.pausedSeconds
+
+			got := s.EnsurePhaseDeadline()
+			switch {
+			case tc.want == nil && got != nil:
+				t.Fatalf("deadline = %s, want nil", got)
+			case tc.want != nil && (got == nil || !got.Equal(*tc.want)):
+				t.Fatalf("deadline = %v, want %s", got, tc.want)
+			case got != s.PhaseEndsAt:
+				t.Fatal("el deadline derivado no quedó asignado")
+			}
+		})
+	}
+
+	t.Run("keeps an existing deadline", func(t *testing.T) {
+		s := sessionIn(SessionStateRunning)
+		endsAt := testStart.Add(40 * minute)
+		s.PhaseEndsAt = &endsAt
+
+		if got := s.EnsurePhaseDeadline(); got != &endsAt {
+			t.Fatalf("deadline = %v, want el existente", got)
+		}
+	})
+}
+
+func ptrTime(t time.Time) *time.Time { return &t }
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// StreakDayLayout es el formato de los días locales de una racha.
+const StreakDayLayout = "2006-01-02"
+
+// MinimumStreakGoal es el mínimo diario para sumar a la racha cuando el
+// usuario no ha configurado un objetivo diario.
+var MinimumStreakGoal = FocusGoal{Metric: GoalMetricPomodoros, Target: 1}
+
+// UserStreak guarda la racha de días consecutivos en que el usuario
+// alcanzó su objetivo diario. Se actualiza de forma incremental al
+// terminar cada focus; LastQualifiedDay es un día local (StreakDayLayout).
+type UserStreak struct {
+	UserID           string    `json:"user_id"`
+	Current          int       `json:"current"`
+	Longest          int       `json:"longest"`
+	LastQualifiedDay string    `json:"last_qualified_day,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Qualify registra que el día local day alcanzó el objetivo: extiende la
+// racha si el día anterior también lo alcanzó o empieza una nueva. Días
+// ya registrados o anteriores al último no modifican la racha.
+func (s *UserStreak) Qualify(day time.Time) {
+	key := day.Format(StreakDayLayout)
+	if s.LastQualifiedDay != "" && key <= s.LastQualifiedDay {
+		return
+	}
+
+	if s.LastQualifiedDay == day.AddDate(0, 0, -1).Format(StreakDayLayout) {
+		s.Current++
+	} else {
+		s.Current = 1
+	}
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	s.LastQualifiedDay = key
+}
+
+// CurrentAt devuelve la racha vigente en el día local today: la racha se
+// rompe si ni hoy ni ayer alcanzaron el objetivo.
+func (s *UserStreak) CurrentAt(today time.Time) int {
+	switch s.LastQualifiedDay {
+	case today.Format(StreakDayLayout), today.AddDate(0, 0, -1).Format(StreakDayLayout):
+		return s.Current
+	}
+	return 0
+}
+
+// StreakRepository define la persistencia de las rachas.
+// GetStreak devuelve (nil, nil) si el usuario aún no tiene racha.
+type StreakRepository interface {
+	GetStreak(userID string) (*UserStreak, error)
+	SaveStreak(streak *UserStreak) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultTagColor se asigna a las etiquetas creadas sin color.
+const DefaultTagColor = "#9E9E9E"
+
+// MaxTaskTags limita las etiquetas asignables a una misma tarea.
+const MaxTaskTags = 20
+
+// ErrTooManyTags indica que se superó MaxTaskTags.
+var ErrTooManyTags = errors.New("task tag limit reached")
+
+// Tag es una etiqueta con color definida por un usuario para clasificar
+// sus tareas más allá del proyecto. Una tarea puede tener varias y una
+// etiqueta puede estar en muchas tareas.
+type Tag struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Color  string `json:"color"` // Hexadecimal, p. ej. "#FF7043"
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagRepository define la persistencia de las etiquetas. Create y Update
+// devuelven ErrTagNameTaken si el usuario ya tiene otra etiqueta con el
+// mismo nombre.
+type TagRepository interface {
+	Create(tag *Tag) error
+	Update(tag *Tag) error
+	Delete(id string) error
+	FindByID(id string) (*Tag, error)
+	FindByUser(userID string) ([]*Tag, error)
+	// FindByIDs devuelve las etiquetas de ids que pertenecen al usuario.
+	FindByIDs(userID string, ids []string) ([]*Tag, error)
+}
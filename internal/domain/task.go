@@ -24,7 +24,11 @@ const (
 // - UserID: propietario de la tarea
 // - Title / Description: contenido básico de la tarea
 // - ProjectID: relación opcional con un proyecto
+// - ParentID / AncestorIDs: jerarquía, con la ruta materializada desde la raíz
+// - TagIDs: etiquetas del usuario asignadas a la tarea
 // - Completed / CompletedAt: permiten saber si está finalizada
+// - EstimatedPomodoros: estimación opcional, comparable con OwnPomodoros
+// - Checklist / AutoComplete: pasos; completarlos todos puede cerrar la tarea
 // - CreatedAt / UpdatedAt: auditoría aplicada por el servicio
 type Task struct {
 	ID          string  `json:"id"`
@@ -33,15 +37,31 @@ type Task struct {
 	Description string  `json:"description"`
 	ProjectID   *string `json:"project_id,omitempty"`
 
+	ParentID    *string  `json:"parent_id,omitempty"`
+	AncestorIDs []string `json:"ancestor_ids,omitempty"`
+
+	TagIDs []string `json:"tag_ids"`
+
 	Status      TaskStatus `json:"status"`
 	Completed   bool       `json:"completed"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
 
+	EstimatedPomodoros *int `json:"estimated_pomodoros,omitempty"`
+	// Las métricas reales incluyen las de todos los descendientes; las Own*
+	// cuentan solo los pomodoros hechos sobre la propia tarea
 	PomodorosCompleted int `json:"pomodoros_completed"`
 	TotalFocusMinutes  int `json:"total_focus_minutes"`
+	OwnPomodoros       int `json:"own_pomodoros"`
+	OwnFocusMinutes    int `json:"own_focus_minutes"`
+
+	Checklist    []ChecklistItem `json:"checklist"`
+	AutoComplete bool            `json:"auto_complete"`
 
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Versión para control de concurrencia optimista
+	Version int64 `json:"version"`
 }
 
 // TaskRepository
@@ -49,15 +69,47 @@ type Task struct {
 // Interfaz que define los métodos necesarios para manipular tareas desde la capa
 // de persistencia. La capa de servicios depende únicamente de esta interfaz,
 // permitiendo intercambiar tecnologías (MongoDB, SQL, archivos, mocks, etc.).
+//
+// Update es condicional a task.Version y devuelve *VersionConflictError si
+// la tarea cambió desde que se leyó; las operaciones atómicas de métricas
+// y estado incrementan la versión.
 type TaskRepository interface {
 	Create(task *Task) error
 	Update(task *Task) error
 	Delete(id string) error
 	FindByID(id string) (*Task, error)
 	FindByUser(userID string) ([]*Task, error)
+	// FindByTags devuelve las tareas del usuario que tienen todas las
+	// etiquetas indicadas.
+	FindByTags(userID string, tagIDs []string) ([]*Task, error)
+	// FindEstimatedCompleted devuelve las tareas del usuario con estimación
+	// completadas desde from, ordenadas por CompletedAt.
+	FindEstimatedCompleted(userID string, from time.Time) ([]*Task, error)
 
-	// Nuevos metodos para metricas pomodoro
+	// Nuevos metodos para metricas pomodoro. AddRealMinutes e
+	// IncrementPomodoroCount suman a los contadores propios de la tarea y a
+	// los acumulados de la tarea y de sus ancestros.
 	UpdateStatus(id string, status TaskStatus) error
 	AddRealMinutes(id string, minutes int) error
 	IncrementPomodoroCount(id string) error
+
+	// Jerarquía
+	FindChildren(parentID string) ([]*Task, error)
+	// MoveDescendants reescribe la ruta de los descendientes de id para
+	// que cuelguen de path (la nueva Path() de la tarea).
+	MoveDescendants(id string, path []string) error
+	// AddMetrics suma los deltas indicados a los acumulados de las tareas
+	// ids, sin tocar sus contadores propios.
+	AddMetrics(ids []string, pomodoros, minutes int) error
+	// MoveMetrics resta los acumulados indicados de las tareas from y los
+	// suma a las tareas to en una sola escritura.
+	MoveMetrics(from, to []string, pomodoros, minutes int) error
+	// RecomputeMetrics recalcula los acumulados de las tareas ids como la
+	// suma de los contadores propios de cada subárbol.
+	RecomputeMetrics(ids []string) error
+	// DeleteSubtree elimina la tarea y todos sus descendientes.
+	DeleteSubtree(id string) error
+
+	// RemoveTag quita la etiqueta de todas las tareas que la tienen.
+	RemoveTag(tagID string) error
 }
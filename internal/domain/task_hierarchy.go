@@ -0,0 +1,47 @@
+package domain
+
+import "errors"
+
+// Errores de la jerarquía de tareas.
+var (
+	ErrTaskCycle       = errors.New("a task cannot be nested under itself or its descendants")
+	ErrTaskHasChildren = errors.New("task has child tasks")
+)
+
+// Path devuelve la ruta materializada que heredan los hijos de la tarea:
+// sus ancestros seguidos de su propio ID.
+func (t *Task) Path() []string {
+	path := make([]string, 0, len(t.AncestorIDs)+1)
+	path = append(path, t.AncestorIDs...)
+	return append(path, t.ID)
+}
+
+// IsAncestorOf indica si la tarea está en la ruta de other.
+func (t *Task) IsAncestorOf(other *Task) bool {
+	for _, id := range other.AncestorIDs {
+		if id == t.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// SetParent cuelga la tarea de parent, o la deja en la raíz si parent es
+// nil. Rechaza los ciclos: la tarea no puede ser su propio padre ni
+// colgar de uno de sus descendientes.
+func (t *Task) SetParent(parent *Task) error {
+	if parent == nil {
+		t.ParentID = nil
+		t.AncestorIDs = nil
+		return nil
+	}
+
+	if parent.ID == t.ID || t.IsAncestorOf(parent) {
+		return ErrTaskCycle
+	}
+
+	parentID := parent.ID
+	t.ParentID = &parentID
+	t.AncestorIDs = parent.Path()
+	return nil
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"pomodoro-backend/internal/domain"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // MongoCycleRepository implementa CycleRepository usando MongoDB.
@@ -20,25 +24,148 @@ func NewMongoCycleRepository(db *mongo.Database) *MongoCycleRepository {
 	}
 }
 
+// mongoCycle representa la forma en que se almacenan los ciclos en MongoDB.
+type mongoCycle struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	SessionID  string             `bson:"session_id"`
+	UserID     string             `bson:"user_id"`
+	TaskID     string             `bson:"task_id,omitempty"`
+	Duration   int                `bson:"duration"`
+	StartedAt  time.Time          `bson:"started_at"`
+	FinishedAt time.Time          `bson:"finished_at"`
+	BreakUsed  bool               `bson:"break_used"`
+}
+
+// EnsureIndexes crea los índices de las consultas de ciclos: por sesión
+// (MarkBreakUsed), por tarea (GetByTask) y por usuario y fecha de fin
+// (GetByUser e informes).
+func (r *MongoCycleRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "session_id", Value: 1}},
+			Options: options.Index().SetName("session_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "finished_at", Value: 1}},
+			Options: options.Index().SetName("task_finished_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "finished_at", Value: 1}},
+			Options: options.Index().SetName("user_finished_at"),
+		},
+	})
+	return err
+}
+
 // Save guarda un ciclo completado.
 func (r *MongoCycleRepository) Save(cycle *domain.PomodoroCycle) error {
-	_, err := r.collection.InsertOne(context.Background(), cycle)
-	return err
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.collection.InsertOne(ctx, domainToMongoCycle(cycle))
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		cycle.ID = oid.Hex()
+	}
+
+	return nil
 }
 
 // GetByTask obtiene todos los ciclos de una tarea específica.
 func (r *MongoCycleRepository) GetByTask(taskID string) ([]*domain.PomodoroCycle, error) {
-	filter := bson.M{"task_id": taskID}
+	return r.find(bson.M{"task_id": taskID})
+}
 
-	cursor, err := r.collection.Find(context.Background(), filter)
+// GetByUser obtiene los ciclos de un usuario, opcionalmente acotados por
+// la fecha de finalización.
+func (r *MongoCycleRepository) GetByUser(userID string, from, to *time.Time) ([]*domain.PomodoroCycle, error) {
+	filter := bson.M{"user_id": userID}
+
+	finished := bson.M{}
+	if from != nil {
+		finished["$gte"] = *from
+	}
+	if to != nil {
+		finished["$lt"] = *to
+	}
+	if len(finished) > 0 {
+		filter["finished_at"] = finished
+	}
+
+	return r.find(filter)
+}
+
+// MarkBreakUsed marca que el ciclo asociado a la sesión tuvo descanso.
+func (r *MongoCycleRepository) MarkBreakUsed(sessionID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"session_id": sessionID},
+		bson.M{"$set": bson.M{"break_used": true}},
+	)
+	return err
+}
+
+// find ejecuta la consulta ordenando los ciclos por fecha de finalización.
+func (r *MongoCycleRepository) find(filter bson.M) ([]*domain.PomodoroCycle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "finished_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
+	defer cursor.Close(ctx)
 
-	var cycles []*domain.PomodoroCycle
-	if err := cursor.All(context.Background(), &cycles); err != nil {
-		return nil, err
+	cycles := []*domain.PomodoroCycle{}
+	for cursor.Next(ctx) {
+		var doc mongoCycle
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		cycles = append(cycles, mongoToDomainCycle(&doc))
 	}
 
-	return cycles, nil
+	return cycles, cursor.Err()
+}
+
+// domainToMongoCycle proyecta un ciclo de dominio hacia su documento Mongo.
+func domainToMongoCycle(c *domain.PomodoroCycle) *mongoCycle {
+	return &mongoCycle{
+		SessionID:  c.SessionID,
+		UserID:     c.UserID,
+		TaskID:     c.TaskID,
+		Duration:   c.Duration,
+		StartedAt:  c.StartedAt,
+		FinishedAt: c.FinishedAt,
+		BreakUsed:  c.BreakUsed,
+	}
+}
+
+// mongoToDomainCycle proyecta un documento Mongo hacia la entidad de dominio.
+func mongoToDomainCycle(m *mongoCycle) *domain.PomodoroCycle {
+	id := ""
+	if !m.ID.IsZero() {
+		id = m.ID.Hex()
+	}
+
+	return &domain.PomodoroCycle{
+		ID:         id,
+		SessionID:  m.SessionID,
+		UserID:     m.UserID,
+		TaskID:     m.TaskID,
+		Duration:   m.Duration,
+		StartedAt:  m.StartedAt,
+		FinishedAt: m.FinishedAt,
+		BreakUsed:  m.BreakUsed,
+	}
 }
@@ -0,0 +1,329 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPreferencesRepository implementa PreferencesRepository (y con ello
+// CadenceRepository) sobre la colección "user_preferences", donde cada
+// usuario tiene un único documento.
+type MongoPreferencesRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoPreferencesRepository construye el repositorio de preferencias.
+func NewMongoPreferencesRepository(db *mongo.Database) *MongoPreferencesRepository {
+	return &MongoPreferencesRepository{
+		col: db.Collection("user_preferences"),
+	}
+}
+
+// -----------------------------
+// Mongo DTO
+// -----------------------------
+
+// mongoPreferences es el documento del usuario. Los campos opcionales son
+// punteros: documentos creados solo con la cadencia no los tienen y se
+// completan con los valores por defecto del dominio.
+type mongoPreferences struct {
+	UserID   string  `bson:"user_id"`
+	Timezone *string `bson:"timezone,omitempty"`
+	Locale   *string `bson:"locale,omitempty"`
+
+	DefaultFocusMinutes *int `bson:"default_focus_minutes,omitempty"`
+	DefaultBreakMinutes *int `bson:"default_break_minutes,omitempty"`
+
+	Cadence *mongoCadence `bson:"cadence,omitempty"`
+
+	AutoStartBreak bool            `bson:"auto_start_break"`
+	DailyGoal      *mongoFocusGoal `bson:"daily_goal,omitempty"`
+	WeeklyGoal     *mongoFocusGoal `bson:"weekly_goal,omitempty"`
+	WeekStartDay   *int            `bson:"week_start_day,omitempty"`
+
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// mongoCadence es el subdocumento "cadence" de las preferencias del usuario.
+type mongoCadence struct {
+	ShortBreakMinutes int `bson:"short_break_minutes"`
+	LongBreakMinutes  int `bson:"long_break_minutes"`
+	PomodorosPerSet   int `bson:"pomodoros_per_set"`
+}
+
+type mongoFocusGoal struct {
+	Metric string `bson:"metric"`
+	Target int    `bson:"target"`
+}
+
+// EnsureIndexes crea el índice único por usuario, que garantiza un solo
+// documento de preferencias aunque dos primeras escrituras compitan. Los
+// duplicados que dejaron esas carreras antes de existir el índice se
+// eliminan conservando el actualizado más recientemente, que es el que
+// GetPreferences tenía más probabilidades de devolver.
+func (r *MongoPreferencesRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := r.removeDuplicateUsers(ctx); err != nil {
+		return err
+	}
+
+	_, err := r.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetName("uniq_user_id").SetUnique(true),
+	})
+	return err
+}
+
+// removeDuplicateUsers deja un único documento por user_id.
+func (r *MongoPreferencesRepository) removeDuplicateUsers(ctx context.Context) error {
+	cursor, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$user_id",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var stale bson.A
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs bson.A `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+		stale = append(stale, group.IDs[1:]...)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = r.col.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": stale}})
+	return err
+}
+
+// -----------------------------
+// PREFERENCIAS
+// -----------------------------
+
+// GetPreferences recupera las preferencias del usuario.
+func (r *MongoPreferencesRepository) GetPreferences(userID string) (*domain.UserPreferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var doc mongoPreferences
+	err := r.col.FindOne(ctx, bson.M{"user_id": userID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainPreferences(&doc), nil
+}
+
+// SavePreferences crea o reemplaza las preferencias del usuario.
+func (r *MongoPreferencesRepository) SavePreferences(p *domain.UserPreferences) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	doc := domainToMongoPreferences(p)
+
+	// $set conserva el _id del documento; los opcionales a nil se eliminan
+	set := bson.M{
+		"timezone":              doc.Timezone,
+		"locale":                doc.Locale,
+		"default_focus_minutes": doc.DefaultFocusMinutes,
+		"default_break_minutes": doc.DefaultBreakMinutes,
+		"auto_start_break":      doc.AutoStartBreak,
+		"week_start_day":        doc.WeekStartDay,
+		"updated_at":            doc.UpdatedAt,
+	}
+	unset := bson.M{}
+	if doc.Cadence != nil {
+		set["cadence"] = doc.Cadence
+	} else {
+		unset["cadence"] = ""
+	}
+	if doc.DailyGoal != nil {
+		set["daily_goal"] = doc.DailyGoal
+	} else {
+		unset["daily_goal"] = ""
+	}
+	if doc.WeeklyGoal != nil {
+		set["weekly_goal"] = doc.WeeklyGoal
+	} else {
+		unset["weekly_goal"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	return r.upsert(ctx, p.UserID, update)
+}
+
+// SaveGoals crea o reemplaza solo los objetivos del usuario; los nil se
+// eliminan del documento.
+func (r *MongoPreferencesRepository) SaveGoals(userID string, daily, weekly *domain.FocusGoal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"updated_at": time.Now()}
+	unset := bson.M{}
+	if daily != nil {
+		set["daily_goal"] = domainToMongoFocusGoal(daily)
+	} else {
+		unset["daily_goal"] = ""
+	}
+	if weekly != nil {
+		set["weekly_goal"] = domainToMongoFocusGoal(weekly)
+	} else {
+		unset["weekly_goal"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	return r.upsert(ctx, userID, update)
+}
+
+// -----------------------------
+// CADENCIA
+// -----------------------------
+
+// GetCadence recupera la cadencia configurada por el usuario.
+func (r *MongoPreferencesRepository) GetCadence(userID string) (*domain.BreakCadence, error) {
+	prefs, err := r.GetPreferences(userID)
+	if err != nil || prefs == nil {
+		return nil, err
+	}
+	return prefs.Cadence, nil
+}
+
+// SaveCadence crea o reemplaza solo la cadencia del usuario.
+func (r *MongoPreferencesRepository) SaveCadence(userID string, c *domain.BreakCadence) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.upsert(ctx, userID, bson.M{"$set": bson.M{
+		"cadence":    domainToMongoCadence(c),
+		"updated_at": time.Now(),
+	}})
+}
+
+// upsert aplica update al documento del usuario y lo crea si no existe.
+// Si dos primeras escrituras compiten, ambas intentan insertar y el índice
+// único rechaza una; al reintentarla ya encuentra el documento de la otra
+// y lo actualiza.
+func (r *MongoPreferencesRepository) upsert(ctx context.Context, userID string, update bson.M) error {
+	filter := bson.M{"user_id": userID}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := r.col.UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		_, err = r.col.UpdateOne(ctx, filter, update, opts)
+	}
+	return err
+}
+
+// -----------------------------
+// MAPPERS
+// -----------------------------
+
+func domainToMongoPreferences(p *domain.UserPreferences) *mongoPreferences {
+	weekStart := int(p.WeekStartDay)
+
+	doc := &mongoPreferences{
+		UserID:              p.UserID,
+		Timezone:            &p.Timezone,
+		Locale:              &p.Locale,
+		DefaultFocusMinutes: &p.DefaultFocusMinutes,
+		DefaultBreakMinutes: &p.DefaultBreakMinutes,
+		AutoStartBreak:      p.AutoStartBreak,
+		WeekStartDay:        &weekStart,
+		UpdatedAt:           p.UpdatedAt,
+	}
+	if p.Cadence != nil {
+		doc.Cadence = domainToMongoCadence(p.Cadence)
+	}
+	doc.DailyGoal = domainToMongoFocusGoal(p.DailyGoal)
+	doc.WeeklyGoal = domainToMongoFocusGoal(p.WeeklyGoal)
+	return doc
+}
+
+func mongoToDomainPreferences(m *mongoPreferences) *domain.UserPreferences {
+	p := domain.DefaultUserPreferences(m.UserID)
+	p.AutoStartBreak = m.AutoStartBreak
+	p.UpdatedAt = m.UpdatedAt
+
+	if m.Timezone != nil {
+		p.Timezone = *m.Timezone
+	}
+	if m.Locale != nil {
+		p.Locale = *m.Locale
+	}
+	if m.DefaultFocusMinutes != nil {
+		p.DefaultFocusMinutes = *m.DefaultFocusMinutes
+	}
+	if m.DefaultBreakMinutes != nil {
+		p.DefaultBreakMinutes = *m.DefaultBreakMinutes
+	}
+	if m.WeekStartDay != nil {
+		p.WeekStartDay = time.Weekday(*m.WeekStartDay)
+	}
+	if m.Cadence != nil {
+		p.Cadence = &domain.BreakCadence{
+			ShortBreakMinutes: m.Cadence.ShortBreakMinutes,
+			LongBreakMinutes:  m.Cadence.LongBreakMinutes,
+			PomodorosPerSet:   m.Cadence.PomodorosPerSet,
+		}
+	}
+	p.DailyGoal = mongoToDomainFocusGoal(m.DailyGoal)
+	p.WeeklyGoal = mongoToDomainFocusGoal(m.WeeklyGoal)
+	return p
+}
+
+func domainToMongoFocusGoal(g *domain.FocusGoal) *mongoFocusGoal {
+	if g == nil {
+		return nil
+	}
+	return &mongoFocusGoal{Metric: string(g.Metric), Target: g.Target}
+}
+
+func mongoToDomainFocusGoal(m *mongoFocusGoal) *domain.FocusGoal {
+	if m == nil {
+		return nil
+	}
+	return &domain.FocusGoal{Metric: domain.GoalMetric(m.Metric), Target: m.Target}
+}
+
+func domainToMongoCadence(c *domain.BreakCadence) *mongoCadence {
+	return &mongoCadence{
+		ShortBreakMinutes: c.ShortBreakMinutes,
+		LongBreakMinutes:  c.LongBreakMinutes,
+		PomodorosPerSet:   c.PomodorosPerSet,
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestPreferencesConcurrentFirstWrites lanza a la vez las primeras
+// escrituras de un usuario: con el índice único y el reintento, todas
+// terminan bien sobre un único documento. Requiere MONGO_TEST_URI.
+func TestPreferencesConcurrentFirstWrites(t *testing.T) {
+	db := testDatabase(t)
+	repo := NewMongoPreferencesRepository(db)
+	if err := repo.EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	const writers = 8
+	cadence := &domain.BreakCadence{ShortBreakMinutes: 5, LongBreakMinutes: 20, PomodorosPerSet: 4}
+	daily := &domain.FocusGoal{Metric: domain.GoalMetricPomodoros, Target: 8}
+
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				errs[i] = repo.SaveCadence("u1", cadence)
+			} else {
+				errs[i] = repo.SaveGoals("u1", daily, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("escritura %d: %v", i, err)
+		}
+	}
+
+	n, err := db.Collection("user_preferences").CountDocuments(context.Background(), bson.M{"user_id": "u1"})
+	if err != nil {
+		t.Fatalf("CountDocuments: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("documentos del usuario = %d, want 1", n)
+	}
+
+	prefs, err := repo.GetPreferences("u1")
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if prefs.Cadence == nil || prefs.DailyGoal == nil {
+		t.Fatalf("se perdió una escritura: cadencia %v, objetivo diario %v", prefs.Cadence, prefs.DailyGoal)
+	}
+}
@@ -0,0 +1,301 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoPresetRepository implementa PresetRepository sobre la colección
+// "session_presets".
+type MongoPresetRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoPresetRepository construye el repositorio de presets.
+func NewMongoPresetRepository(db *mongo.Database) *MongoPresetRepository {
+	return &MongoPresetRepository{
+		col: db.Collection("session_presets"),
+	}
+}
+
+// -----------------------------
+// Mongo DTO
+// -----------------------------
+
+type mongoPreset struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	UserID       string             `bson:"user_id"`
+	Name         string             `bson:"name"`
+	FocusMinutes int                `bson:"focus_minutes"`
+	BreakMinutes int                `bson:"break_minutes"`
+	IsDefault    bool               `bson:"is_default"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// Nombres de los índices únicos; translatePresetWriteError los usa para
+// saber qué restricción rechazó la escritura.
+const (
+	presetNameIndex    = "uniq_preset_name_per_user"
+	defaultPresetIndex = "uniq_default_preset_per_user"
+)
+
+// EnsureIndexes crea los índices de la colección: el nombre de un preset
+// es único por usuario y cada usuario tiene como mucho un predeterminado.
+func (r *MongoPresetRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := r.removeDuplicateDefaults(ctx); err != nil {
+		return err
+	}
+
+	_, err := r.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+			Options: options.Index().SetName(presetNameIndex).SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().
+				SetName(defaultPresetIndex).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"is_default": true}),
+		},
+	})
+	return err
+}
+
+// removeDuplicateDefaults deja un único predeterminado por usuario, el
+// actualizado más recientemente, para poder crear el índice parcial.
+func (r *MongoPresetRepository) removeDuplicateDefaults(ctx context.Context) error {
+	cursor, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"is_default": true}}},
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: -1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$user_id",
+			"ids":   bson.M{"$push": "$_id"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var stale bson.A
+	for cursor.Next(ctx) {
+		var group struct {
+			IDs bson.A `bson:"ids"`
+		}
+		if err := cursor.Decode(&group); err != nil {
+			return err
+		}
+		stale = append(stale, group.IDs[1:]...)
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	_, err = r.col.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": stale}}, bson.M{"$set": bson.M{"is_default": false}})
+	return err
+}
+
+// -----------------------------
+// CREATE / UPDATE / DELETE
+// -----------------------------
+
+func (r *MongoPresetRepository) Create(p *domain.SessionPreset) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.col.InsertOne(ctx, domainToMongoPreset(p))
+	if err != nil {
+		return translatePresetWriteError(err)
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		p.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *MongoPresetRepository) Update(p *domain.SessionPreset) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(p.ID)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.col.ReplaceOne(ctx, bson.M{"_id": oid}, domainToMongoPreset(p))
+	if err != nil {
+		return translatePresetWriteError(err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *MongoPresetRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// ClearDefault desmarca el preset predeterminado del usuario, salvo el
+// indicado en exceptID (puede ser vacío).
+func (r *MongoPresetRepository) ClearDefault(userID, exceptID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "is_default": true}
+	if oid, err := primitive.ObjectIDFromHex(exceptID); err == nil {
+		filter["_id"] = bson.M{"$ne": oid}
+	}
+
+	_, err := r.col.UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"is_default": false,
+		"updated_at": time.Now(),
+	}})
+	return err
+}
+
+// -----------------------------
+// FIND
+// -----------------------------
+
+func (r *MongoPresetRepository) FindByID(id string) (*domain.SessionPreset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc mongoPreset
+	if err := r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainPreset(&doc), nil
+}
+
+// FindByUser devuelve los presets del usuario ordenados por nombre.
+func (r *MongoPresetRepository) FindByUser(userID string) ([]*domain.SessionPreset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.col.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	presets := []*domain.SessionPreset{}
+	for cursor.Next(ctx) {
+		var doc mongoPreset
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		presets = append(presets, mongoToDomainPreset(&doc))
+	}
+
+	return presets, cursor.Err()
+}
+
+func (r *MongoPresetRepository) FindDefault(userID string) (*domain.SessionPreset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var doc mongoPreset
+	err := r.col.FindOne(ctx, bson.M{"user_id": userID, "is_default": true}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainPreset(&doc), nil
+}
+
+// -----------------------------
+// MAPPERS
+// -----------------------------
+
+// translatePresetWriteError distingue por el nombre del índice qué
+// restricción única rechazó la escritura.
+func translatePresetWriteError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), defaultPresetIndex) {
+		return domain.ErrDefaultPresetTaken
+	}
+	return domain.ErrPresetNameTaken
+}
+
+func domainToMongoPreset(p *domain.SessionPreset) *mongoPreset {
+	return &mongoPreset{
+		UserID:       p.UserID,
+		Name:         p.Name,
+		FocusMinutes: p.FocusMinutes,
+		BreakMinutes: p.BreakMinutes,
+		IsDefault:    p.IsDefault,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+func mongoToDomainPreset(m *mongoPreset) *domain.SessionPreset {
+	id := ""
+	if !m.ID.IsZero() {
+		id = m.ID.Hex()
+	}
+
+	return &domain.SessionPreset{
+		ID:           id,
+		UserID:       m.UserID,
+		Name:         m.Name,
+		FocusMinutes: m.FocusMinutes,
+		BreakMinutes: m.BreakMinutes,
+		IsDefault:    m.IsDefault,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}
+}
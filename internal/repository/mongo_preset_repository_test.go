@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// TestPresetUniqueIndexes comprueba que cada índice único se traduce a su
+// propio error de dominio. Requiere MONGO_TEST_URI.
+func TestPresetUniqueIndexes(t *testing.T) {
+	db := testDatabase(t)
+	repo := NewMongoPresetRepository(db)
+	if err := repo.EnsureIndexes(); err != nil {
+		t.Fatalf("EnsureIndexes: %v", err)
+	}
+
+	now := time.Now()
+	preset := func(name string, isDefault bool) *domain.SessionPreset {
+		return &domain.SessionPreset{
+			UserID: "u1", Name: name, FocusMinutes: 25, BreakMinutes: 5,
+			IsDefault: isDefault, CreatedAt: now, UpdatedAt: now,
+		}
+	}
+
+	if err := repo.Create(preset("corto", true)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Create(preset("largo", false)); err != nil {
+		t.Fatalf("Create no predeterminado: %v", err)
+	}
+
+	if err := repo.Create(preset("medio", true)); !errors.Is(err, domain.ErrDefaultPresetTaken) {
+		t.Errorf("segundo predeterminado: err = %v, want ErrDefaultPresetTaken", err)
+	}
+	if err := repo.Create(preset("corto", false)); !errors.Is(err, domain.ErrPresetNameTaken) {
+		t.Errorf("nombre repetido: err = %v, want ErrPresetNameTaken", err)
+	}
+}
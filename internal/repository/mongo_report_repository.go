@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoReportRepository implementa ReportRepository con pipelines de
+// agregación sobre las colecciones de sesiones, ciclos y tareas.
+type MongoReportRepository struct {
+	sessions *mongo.Collection
+	cycles   *mongo.Collection
+	tasks    *mongo.Collection
+}
+
+// NewMongoReportRepository construye el repositorio de informes.
+func NewMongoReportRepository(db *mongo.Database) *MongoReportRepository {
+	return &MongoReportRepository{
+		sessions: db.Collection("sessions"),
+		cycles:   db.Collection("cycles"),
+		tasks:    db.Collection("tasks"),
+	}
+}
+
+// projectKeyExpr agrupa por proyecto; las sesiones y tareas sin proyecto
+// caen en la clave vacía.
+var projectKeyExpr = bson.M{"$ifNull": bson.A{"$project_id", ""}}
+
+// BuildReport ejecuta los pipelines del informe y combina sus resultados.
+func (r *MongoReportRepository) BuildReport(report *domain.ProductivityReport) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	inRange := bson.M{"$gte": report.From, "$lt": report.To}
+
+	// Con filtro por etiqueta, sesiones y ciclos se limitan a las tareas
+	// etiquetadas, resueltas de antemano.
+	sessionMatch := bson.M{"user_id": report.UserID, "finished_at": inRange}
+	cycleMatch := bson.M{"user_id": report.UserID, "finished_at": inRange}
+	taskMatch := bson.M{"user_id": report.UserID, "completed": true, "completed_at": inRange}
+	if report.TagID != "" {
+		taskIDs, err := r.taggedTaskIDs(ctx, report.UserID, report.TagID)
+		if err != nil {
+			return err
+		}
+		sessionMatch["task_id"] = bson.M{"$in": taskIDs}
+		cycleMatch["task_id"] = bson.M{"$in": taskIDs}
+		taskMatch["tag_ids"] = report.TagID
+	}
+
+	projects := map[string]*domain.ProjectBreakdown{}
+	project := func(id string) *domain.ProjectBreakdown {
+		if p, ok := projects[id]; ok {
+			return p
+		}
+		p := &domain.ProjectBreakdown{ProjectID: id}
+		projects[id] = p
+		return p
+	}
+
+	// Focus terminados y descansos saltados, por proyecto
+	var focusRows []struct {
+		ProjectID string `bson:"_id"`
+		Pomodoros int    `bson:"pomodoros"`
+		Seconds   int    `bson:"seconds"`
+		Skipped   int    `bson:"skipped"`
+	}
+	err := aggregate(ctx, r.sessions, mongo.Pipeline{
+		{{Key: "$match", Value: sessionMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       projectKeyExpr,
+			"pomodoros": bson.M{"$sum": 1},
+			"seconds":   bson.M{"$sum": focusedSecondsExpr},
+			"skipped": bson.M{"$sum": bson.M{"$cond": bson.A{
+				bson.M{"$eq": bson.A{"$state", string(domain.SessionStateBreakSkipped)}}, 1, 0,
+			}}},
+		}}},
+	}, &focusRows)
+	if err != nil {
+		return err
+	}
+
+	totalSeconds := 0
+	for _, row := range focusRows {
+		p := project(row.ProjectID)
+		p.Pomodoros = row.Pomodoros
+		p.FocusMinutes = row.Seconds / 60
+
+		totalSeconds += row.Seconds
+		report.Pomodoros += row.Pomodoros
+		report.BreaksSkipped += row.Skipped
+	}
+	report.FocusMinutes = totalSeconds / 60
+
+	// Interrupciones: pausas del focus registradas dentro del rango
+	var interruptionRows []struct {
+		Count int `bson:"count"`
+	}
+	pausedMatch := bson.M{
+		"user_id": report.UserID,
+		"events": bson.M{"$elemMatch": bson.M{
+			"type": string(domain.SessionEventPaused),
+			"at":   inRange,
+		}},
+	}
+	if taskIDs, ok := sessionMatch["task_id"]; ok {
+		pausedMatch["task_id"] = taskIDs
+	}
+	err = aggregate(ctx, r.sessions, mongo.Pipeline{
+		{{Key: "$match", Value: pausedMatch}},
+		{{Key: "$unwind", Value: "$events"}},
+		{{Key: "$match", Value: bson.M{
+			"events.type": string(domain.SessionEventPaused),
+			"events.at":   inRange,
+		}}},
+		{{Key: "$count", Value: "count"}},
+	}, &interruptionRows)
+	if err != nil {
+		return err
+	}
+	if len(interruptionRows) > 0 {
+		report.Interruptions = interruptionRows[0].Count
+	}
+
+	// Descansos tomados: ciclos del rango cuyo break se completó
+	var breakRows []struct {
+		Taken int `bson:"taken"`
+	}
+	err = aggregate(ctx, r.cycles, mongo.Pipeline{
+		{{Key: "$match", Value: cycleMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   nil,
+			"taken": bson.M{"$sum": bson.M{"$cond": bson.A{"$break_used", 1, 0}}},
+		}}},
+	}, &breakRows)
+	if err != nil {
+		return err
+	}
+	if len(breakRows) > 0 {
+		report.BreaksTaken = breakRows[0].Taken
+	}
+
+	// Tareas completadas, por proyecto
+	var taskRows []struct {
+		ProjectID string `bson:"_id"`
+		Count     int    `bson:"count"`
+	}
+	err = aggregate(ctx, r.tasks, mongo.Pipeline{
+		{{Key: "$match", Value: taskMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   projectKeyExpr,
+			"count": bson.M{"$sum": 1},
+		}}},
+	}, &taskRows)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range taskRows {
+		project(row.ProjectID).CompletedTasks = row.Count
+		report.CompletedTasks += row.Count
+	}
+
+	report.Projects = make([]domain.ProjectBreakdown, 0, len(projects))
+	for _, p := range projects {
+		report.Projects = append(report.Projects, *p)
+	}
+	sort.Slice(report.Projects, func(i, j int) bool {
+		a, b := report.Projects[i], report.Projects[j]
+		if a.FocusMinutes != b.FocusMinutes {
+			return a.FocusMinutes > b.FocusMinutes
+		}
+		return a.ProjectID < b.ProjectID
+	})
+
+	report.Tags, err = r.tagBreakdown(ctx, sessionMatch)
+	return err
+}
+
+// tagBreakdown suma el foco de las sesiones por cada etiqueta de su tarea.
+// task_id se guarda como hex, así que se convierte para el $lookup.
+func (r *MongoReportRepository) tagBreakdown(ctx context.Context, sessionMatch bson.M) ([]domain.TagBreakdown, error) {
+	var rows []struct {
+		TagID     string `bson:"_id"`
+		Pomodoros int    `bson:"pomodoros"`
+		Seconds   int    `bson:"seconds"`
+	}
+	err := aggregate(ctx, r.sessions, mongo.Pipeline{
+		{{Key: "$match", Value: sessionMatch}},
+		{{Key: "$match", Value: bson.M{"task_id": bson.M{"$type": "string"}}}},
+		{{Key: "$addFields", Value: bson.M{
+			"task_oid": bson.M{"$convert": bson.M{
+				"input": "$task_id", "to": "objectId", "onError": nil, "onNull": nil,
+			}},
+		}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.tasks.Name(),
+			"localField":   "task_oid",
+			"foreignField": "_id",
+			"as":           "task",
+		}}},
+		{{Key: "$unwind", Value: "$task"}},
+		{{Key: "$unwind", Value: "$task.tag_ids"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       "$task.tag_ids",
+			"pomodoros": bson.M{"$sum": 1},
+			"seconds":   bson.M{"$sum": focusedSecondsExpr},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "seconds", Value: -1}, {Key: "_id", Value: 1}}}},
+	}, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]domain.TagBreakdown, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, domain.TagBreakdown{
+			TagID:        row.TagID,
+			Pomodoros:    row.Pomodoros,
+			FocusMinutes: row.Seconds / 60,
+		})
+	}
+	return tags, nil
+}
+
+// taggedTaskIDs devuelve los IDs (hex) de las tareas del usuario con la
+// etiqueta, en el formato en que las sesiones y ciclos las referencian.
+func (r *MongoReportRepository) taggedTaskIDs(ctx context.Context, userID, tagID string) ([]string, error) {
+	var rows []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err := aggregate(ctx, r.tasks, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"user_id": userID, "tag_ids": tagID}}},
+		{{Key: "$project", Value: bson.M{"_id": 1}}},
+	}, &rows)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID.Hex())
+	}
+	return ids, nil
+}
+
+// aggregate ejecuta un pipeline y decodifica todas las filas en out.
+func aggregate(ctx context.Context, col *mongo.Collection, pipeline mongo.Pipeline, out any) error {
+	cursor, err := col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	return cursor.All(ctx, out)
+}
@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"pomodoro-backend/internal/domain"
@@ -9,6 +13,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // MongoSessionRepository implementa SessionRepository utilizando MongoDB
@@ -28,19 +33,40 @@ func NewMongoSessionRepository(db *mongo.Database) *MongoSessionRepository {
 // mongoSession representa la forma en que se almacenan las sesiones
 // físicamente en MongoDB.
 type mongoSession struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty"`
-	UserID        string             `bson:"user_id"`
-	ProjectID     *string            `bson:"project_id,omitempty"`
-	TaskID        *string            `bson:"task_id,omitempty"`
-	FocusMinutes  int                `bson:"focus_minutes"`
-	BreakMinutes  int                `bson:"break_minutes"`
-	State         string             `bson:"state"`
-	StartedAt     time.Time          `bson:"started_at"`
-	PausedAt      *time.Time         `bson:"paused_at,omitempty"`
-	FinishedAt    *time.Time         `bson:"finished_at,omitempty"`
-	CreatedAt     time.Time          `bson:"created_at"`
-	UpdatedAt     time.Time          `bson:"updated_at"`
-	Interruptions int                `bson:"interruptions"`
+	ID              primitive.ObjectID  `bson:"_id,omitempty"`
+	UserID          string              `bson:"user_id"`
+	ProjectID       *string             `bson:"project_id,omitempty"`
+	TaskID          *string             `bson:"task_id,omitempty"`
+	FocusMinutes    int                 `bson:"focus_minutes"`
+	BreakMinutes    int                 `bson:"break_minutes"`
+	State           string              `bson:"state"`
+	SetPosition     int                 `bson:"set_position"`
+	LongBreak       bool                `bson:"long_break"`
+	StartedAt       time.Time           `bson:"started_at"`
+	PausedAt        *time.Time          `bson:"paused_at,omitempty"`
+	FinishedAt      *time.Time          `bson:"finished_at,omitempty"`
+	BreakStartedAt  *time.Time          `bson:"break_started_at,omitempty"`
+	BreakFinishedAt *time.Time          `bson:"break_finished_at,omitempty"`
+	PhaseEndsAt     *time.Time          `bson:"phase_ends_at,omitempty"`
+	CreatedAt       time.Time           `bson:"created_at"`
+	UpdatedAt       time.Time           `bson:"updated_at"`
+	Interruptions   int                 `bson:"interruptions"`
+	PausedSeconds   int                 `bson:"paused_seconds"`
+	FocusedSeconds  int                 `bson:"focused_seconds"`
+	Events          []mongoSessionEvent `bson:"events,omitempty"`
+	Version         int64               `bson:"version"`
+}
+
+// mongoSessionEvent representa un evento de la línea de tiempo embebido
+// en el documento de la sesión.
+type mongoSessionEvent struct {
+	Type   string    `bson:"type"`
+	At     time.Time `bson:"at"`
+	State  string    `bson:"state"`
+	Reason string    `bson:"reason,omitempty"`
+
+	Interruption string `bson:"interruption,omitempty"`
+	Minutes      int    `bson:"minutes,omitempty"`
 }
 
 // CreateSession inserta una nueva sesión en la colección de MongoDB.
@@ -48,11 +74,12 @@ func (r *MongoSessionRepository) CreateSession(s *domain.Session) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	s.Version = 1
 	doc := domainToMongoSession(s)
 
 	res, err := r.col.InsertOne(ctx, doc)
 	if err != nil {
-		return err
+		return translateSessionWriteError(err)
 	}
 
 	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
@@ -62,8 +89,10 @@ func (r *MongoSessionRepository) CreateSession(s *domain.Session) error {
 	return nil
 }
 
-// UpdateSession reemplaza el documento asociado a la sesión por su nueva versión.
-func (r *MongoSessionRepository) UpdateSession(s *domain.Session) error {
+// TransitionSession aplica una transición de estado con un único
+// findAndModify condicionado al estado y la versión previos, de modo que
+// de dos transiciones concurrentes sobre la misma sesión solo una gana.
+func (r *MongoSessionRepository) TransitionSession(s *domain.Session, from domain.SessionState) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -72,10 +101,45 @@ func (r *MongoSessionRepository) UpdateSession(s *domain.Session) error {
 		return err
 	}
 
+	filter := versionedFilter(oid, s.Version)
+	filter["state"] = string(from)
+
 	doc := domainToMongoSession(s)
+	doc.Version = s.Version + 1
 
-	_, err = r.col.ReplaceOne(ctx, bson.M{"_id": oid}, doc)
-	return err
+	opts := options.FindOneAndReplace().SetReturnDocument(options.After)
+
+	var updated mongoSession
+	err = r.col.FindOneAndReplace(ctx, filter, doc, opts).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.diagnoseTransition(ctx, oid, s, from)
+	}
+	if err != nil {
+		return translateSessionWriteError(err)
+	}
+
+	s.Version = updated.Version
+	return nil
+}
+
+// diagnoseTransition explica por qué una transición condicional no
+// encontró el documento: no existe, cambió de estado o cambió de versión.
+func (r *MongoSessionRepository) diagnoseTransition(ctx context.Context, oid primitive.ObjectID, s *domain.Session, from domain.SessionState) error {
+	var current mongoSession
+	err := r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&current)
+	if err != nil {
+		return err
+	}
+
+	if current.State != string(from) {
+		return domain.ErrStateConflict
+	}
+
+	return &domain.VersionConflictError{
+		Entity:  "session",
+		ID:      s.ID,
+		Version: s.Version,
+	}
 }
 
 // FindByID recupera una sesión con base en su identificador único.
@@ -96,26 +160,414 @@ func (r *MongoSessionRepository) FindByID(id string) (*domain.Session, error) {
 	return mongoToDomainSession(&doc), nil
 }
 
-// domainToMongo proyecta una entidad de dominio hacia su representación
-// específica para MongoDB.
+// FindByStates devuelve todas las sesiones cuyo estado coincide con alguno
+// de los indicados.
+func (r *MongoSessionRepository) FindByStates(states ...domain.SessionState) ([]*domain.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.col.Find(ctx, bson.M{"state": bson.M{"$in": sessionStateValues(states)}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*domain.Session
+	for cursor.Next(ctx) {
+		var doc mongoSession
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, mongoToDomainSession(&doc))
+	}
+
+	return sessions, cursor.Err()
+}
+
+// FindLatestByUser recupera la última sesión iniciada por el usuario.
+func (r *MongoSessionRepository) FindLatestByUser(userID string) (*domain.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}})
+
+	var doc mongoSession
+	err := r.col.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainSession(&doc), nil
+}
+
+// FindActiveByUser recupera la sesión activa del usuario, si existe.
+func (r *MongoSessionRepository) FindActiveByUser(userID string) (*domain.Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"user_id": userID,
+		"state":   bson.M{"$in": sessionStateValues(domain.ActiveSessionStates)},
+	}
+
+	var doc mongoSession
+	err := r.col.FindOne(ctx, filter).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainSession(&doc), nil
+}
+
+// EnsureIndexes crea los índices que soportan las consultas de historial.
+// Es idempotente y debe ejecutarse al iniciar el servicio.
+func (r *MongoSessionRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "started_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "started_at", Value: -1}}},
+		{Keys: bson.D{{Key: "state", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "finished_at", Value: 1}}},
+		{
+			// Garantiza a nivel de base de datos una sola sesión activa por
+			// usuario, incluso ante peticiones concurrentes.
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().
+				SetName("uniq_active_session_per_user").
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{
+					"state": bson.M{"$in": sessionStateValues(domain.ActiveSessionStates)},
+				}),
+		},
+	})
+	return err
+}
+
+// FindSessions ejecuta una consulta paginada por cursor. El orden es
+// (started_at, _id), de modo que el cursor es estable aunque varias
+// sesiones compartan started_at.
+func (r *MongoSessionRepository) FindSessions(q domain.SessionQuery) (*domain.SessionPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if q.UserID != "" {
+		filter["user_id"] = q.UserID
+	}
+	if q.TaskID != "" {
+		filter["task_id"] = q.TaskID
+	}
+	if q.ProjectID != "" {
+		filter["project_id"] = q.ProjectID
+	}
+	if q.State != "" {
+		filter["state"] = string(q.State)
+	}
+
+	started := bson.M{}
+	if q.From != nil {
+		started["$gte"] = *q.From
+	}
+	if q.To != nil {
+		started["$lt"] = *q.To
+	}
+	if len(started) > 0 {
+		filter["started_at"] = started
+	}
+
+	direction, op := -1, "$lt"
+	if q.Ascending {
+		direction, op = 1, "$gt"
+	}
+
+	if q.Cursor != "" {
+		at, oid, err := decodeSessionCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["$or"] = bson.A{
+			bson.M{"started_at": bson.M{op: at}},
+			bson.M{"started_at": at, "_id": bson.M{op: oid}},
+		}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "started_at", Value: direction}, {Key: "_id", Value: direction}}).
+		SetLimit(int64(q.Limit + 1))
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoSession
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	page := &domain.SessionPage{Sessions: []*domain.Session{}}
+	for i := range docs {
+		if i == q.Limit {
+			last := docs[i-1]
+			page.NextCursor = encodeSessionCursor(last.StartedAt, last.ID)
+			break
+		}
+		page.Sessions = append(page.Sessions, mongoToDomainSession(&docs[i]))
+	}
+
+	return page, nil
+}
+
+// encodeSessionCursor serializa la posición (started_at, _id) de la última
+// sesión devuelta en un valor opaco para el cliente.
+func encodeSessionCursor(at time.Time, id primitive.ObjectID) string {
+	raw := strconv.FormatInt(at.UnixMilli(), 10) + ":" + id.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeSessionCursor es la inversa de encodeSessionCursor.
+func decodeSessionCursor(cursor string) (time.Time, primitive.ObjectID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	millis, hex, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return time.Time{}, primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	ms, err := strconv.ParseInt(millis, 10, 64)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	oid, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return time.Time{}, primitive.NilObjectID, domain.ErrInvalidCursor
+	}
+
+	return time.UnixMilli(ms).UTC(), oid, nil
+}
+
+// CountInterruptions agrega los eventos de pausa de las sesiones que
+// cumplen el filtro, agrupados por clasificación y motivo.
+func (r *MongoSessionRepository) CountInterruptions(filter domain.InterruptionFilter) ([]domain.InterruptionCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match := bson.M{}
+	if filter.UserID != "" {
+		match["user_id"] = filter.UserID
+	}
+	if filter.TaskID != "" {
+		match["task_id"] = filter.TaskID
+	}
+
+	eventMatch := bson.M{"events.type": string(domain.SessionEventPaused)}
+	at := bson.M{}
+	if filter.From != nil {
+		at["$gte"] = *filter.From
+	}
+	if filter.To != nil {
+		at["$lt"] = *filter.To
+	}
+	if len(at) > 0 {
+		eventMatch["events.at"] = at
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$events"}},
+		{{Key: "$match", Value: eventMatch}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"type":   "$events.interruption",
+				"reason": "$events.reason",
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		ID struct {
+			Type   string `bson:"type"`
+			Reason string `bson:"reason"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make([]domain.InterruptionCount, 0, len(rows))
+	for _, row := range rows {
+		counts = append(counts, domain.InterruptionCount{
+			Type:   domain.InterruptionType(row.ID.Type),
+			Reason: row.ID.Reason,
+			Count:  row.Count,
+		})
+	}
+
+	return counts, nil
+}
+
+// SumFocus totaliza los focus del usuario terminados en [from, to). Las
+// sesiones anteriores al registro de focused_seconds cuentan su duración
+// planificada completa.
+func (r *MongoSessionRepository) SumFocus(userID string, from, to time.Time) (*domain.FocusTotals, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":     userID,
+			"finished_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"pomodoros": bson.M{"$sum": 1},
+			"seconds":   bson.M{"$sum": focusedSecondsExpr},
+		}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Pomodoros int `bson:"pomodoros"`
+		Seconds   int `bson:"seconds"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	totals := &domain.FocusTotals{}
+	if len(rows) > 0 {
+		totals.Pomodoros = rows[0].Pomodoros
+		totals.FocusedSeconds = rows[0].Seconds
+	}
+	return totals, nil
+}
+
+// FindFocusIntervals devuelve los focus del usuario terminados en
+// [from, to), proyectando solo los campos necesarios.
+func (r *MongoSessionRepository) FindFocusIntervals(userID string, from, to time.Time) ([]domain.FocusInterval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":     userID,
+			"finished_at": bson.M{"$gte": from, "$lt": to},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"started_at":  1,
+			"finished_at": 1,
+			"seconds":     focusedSecondsExpr,
+		}}},
+	}
+
+	cursor, err := r.col.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		StartedAt  time.Time `bson:"started_at"`
+		FinishedAt time.Time `bson:"finished_at"`
+		Seconds    int       `bson:"seconds"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	intervals := make([]domain.FocusInterval, 0, len(rows))
+	for _, row := range rows {
+		intervals = append(intervals, domain.FocusInterval{
+			StartedAt:      row.StartedAt,
+			FinishedAt:     row.FinishedAt,
+			FocusedSeconds: row.Seconds,
+		})
+	}
+	return intervals, nil
+}
+
+// focusedSecondsExpr es la expresión de agregación de los segundos
+// enfocados de una sesión, con la duración planificada como respaldo.
+var focusedSecondsExpr = bson.M{"$ifNull": bson.A{
+	"$focused_seconds",
+	bson.M{"$multiply": bson.A{"$focus_minutes", 60}},
+}}
+
+// translateSessionWriteError convierte la violación del índice de sesión
+// activa única en el error de dominio correspondiente.
+func translateSessionWriteError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrActiveSessionExists
+	}
+	return err
+}
+
+func sessionStateValues(states []domain.SessionState) []string {
+	values := make([]string, 0, len(states))
+	for _, st := range states {
+		values = append(values, string(st))
+	}
+	return values
+}
+
+// domainToMongoSession proyecta una entidad de dominio hacia su representación
+// específica para MongoDB. Todo campo nuevo de domain.Session debe reflejarse
+// aquí y en mongoToDomainSession; TestSessionMappingRoundTrip falla si no.
 func domainToMongoSession(s *domain.Session) *mongoSession {
 	return &mongoSession{
-		UserID:        s.UserID,
-		ProjectID:     s.ProjectID,
-		TaskID:        s.TaskID,
-		FocusMinutes:  s.FocusMinutes,
-		BreakMinutes:  s.BreakMinutes,
-		State:         string(s.State),
-		StartedAt:     s.StartedAt,
-		PausedAt:      s.PausedAt,
-		FinishedAt:    s.FinishedAt,
-		CreatedAt:     s.CreatedAt,
-		UpdatedAt:     s.UpdatedAt,
-		Interruptions: s.Interruptions,
-	}
-}
-
-// mongoToDomain proyecta un documento de MongoDB hacia la entidad de dominio.
+		UserID:          s.UserID,
+		ProjectID:       s.ProjectID,
+		TaskID:          s.TaskID,
+		FocusMinutes:    s.FocusMinutes,
+		BreakMinutes:    s.BreakMinutes,
+		State:           string(s.State),
+		SetPosition:     s.SetPosition,
+		LongBreak:       s.LongBreak,
+		StartedAt:       s.StartedAt,
+		PausedAt:        s.PausedAt,
+		FinishedAt:      s.FinishedAt,
+		BreakStartedAt:  s.BreakStartedAt,
+		BreakFinishedAt: s.BreakFinishedAt,
+		PhaseEndsAt:     s.PhaseEndsAt,
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+		Interruptions:   s.Interruptions,
+		PausedSeconds:   s.PausedSeconds,
+		FocusedSeconds:  s.FocusedSeconds,
+		Events:          domainToMongoSessionEvents(s.Events),
+		Version:         s.Version,
+	}
+}
+
+// mongoToDomainSession proyecta un documento de MongoDB hacia la entidad de dominio.
 func mongoToDomainSession(m *mongoSession) *domain.Session {
 	id := ""
 	if !m.ID.IsZero() {
@@ -123,18 +575,63 @@ func mongoToDomainSession(m *mongoSession) *domain.Session {
 	}
 
 	return &domain.Session{
-		ID:            id,
-		UserID:        m.UserID,
-		ProjectID:     m.ProjectID,
-		TaskID:        m.TaskID,
-		FocusMinutes:  m.FocusMinutes,
-		BreakMinutes:  m.BreakMinutes,
-		State:         domain.SessionState(m.State),
-		StartedAt:     m.StartedAt,
-		PausedAt:      m.PausedAt,
-		FinishedAt:    m.FinishedAt,
-		CreatedAt:     m.CreatedAt,
-		UpdatedAt:     m.UpdatedAt,
-		Interruptions: m.Interruptions,
+		ID:              id,
+		UserID:          m.UserID,
+		ProjectID:       m.ProjectID,
+		TaskID:          m.TaskID,
+		FocusMinutes:    m.FocusMinutes,
+		BreakMinutes:    m.BreakMinutes,
+		State:           domain.SessionState(m.State),
+		SetPosition:     m.SetPosition,
+		LongBreak:       m.LongBreak,
+		StartedAt:       m.StartedAt,
+		PausedAt:        m.PausedAt,
+		FinishedAt:      m.FinishedAt,
+		BreakStartedAt:  m.BreakStartedAt,
+		BreakFinishedAt: m.BreakFinishedAt,
+		PhaseEndsAt:     m.PhaseEndsAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+		Interruptions:   m.Interruptions,
+		PausedSeconds:   m.PausedSeconds,
+		FocusedSeconds:  m.FocusedSeconds,
+		Events:          mongoToDomainSessionEvents(m.Events),
+		Version:         m.Version,
+	}
+}
+
+func domainToMongoSessionEvents(events []domain.SessionEvent) []mongoSessionEvent {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]mongoSessionEvent, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, mongoSessionEvent{
+			Type:   string(e.Type),
+			At:     e.At,
+			State:  string(e.State),
+			Reason: e.Reason,
+
+			Interruption: string(e.Interruption),
+			Minutes:      e.Minutes,
+		})
+	}
+	return docs
+}
+
+func mongoToDomainSessionEvents(docs []mongoSessionEvent) []domain.SessionEvent {
+	events := make([]domain.SessionEvent, 0, len(docs))
+	for _, d := range docs {
+		events = append(events, domain.SessionEvent{
+			Type:   domain.SessionEventType(d.Type),
+			At:     d.At,
+			State:  domain.SessionState(d.State),
+			Reason: d.Reason,
+
+			Interruption: domain.InterruptionType(d.Interruption),
+			Minutes:      d.Minutes,
+		})
 	}
+	return events
 }
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestSessionMappingRoundTrip garantiza que todo campo de domain.Session
+// sobrevive a domainToMongoSession → BSON → mongoToDomainSession. La sesión
+// se rellena por reflexión, de modo que un campo nuevo que no se mapee hace
+// fallar el test sin tener que tocarlo.
+func TestSessionMappingRoundTrip(t *testing.T) {
+	want := &domain.Session{}
+	fillValue(t, reflect.ValueOf(want).Elem(), "Session")
+	want.ID = primitive.NewObjectID().Hex()
+
+	doc := domainToMongoSession(want)
+	doc.ID, _ = primitive.ObjectIDFromHex(want.ID)
+
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var decoded mongoSession
+	if err := bson.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("bson.Unmarshal: %v", err)
+	}
+
+	got := mongoToDomainSession(&decoded)
+	assertSameFields(t, want, got)
+}
+
+// TestSessionRepositoryRoundTrip repite la comprobación contra un mongod
+// real con CreateSession/FindByID. Requiere MONGO_TEST_URI.
+func TestSessionRepositoryRoundTrip(t *testing.T) {
+	db := testDatabase(t)
+	repo := NewMongoSessionRepository(db)
+
+	want := &domain.Session{}
+	fillValue(t, reflect.ValueOf(want).Elem(), "Session")
+	want.ID = ""
+
+	if err := repo.CreateSession(want); err != nil {
+		t.Fatalf("CreateSession: %v", err)
+	}
+
+	got, err := repo.FindByID(want.ID)
+	if err != nil {
+		t.Fatalf("FindByID: %v", err)
+	}
+	assertSameFields(t, want, got)
+}
+
+// testDatabase conecta con el mongod indicado en MONGO_TEST_URI y devuelve
+// una base de datos desechable que se elimina al terminar el test.
+func testDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI no definido")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("mongo.Connect: %v", err)
+	}
+
+	db := client.Database("pomodoro_test_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = db.Drop(ctx)
+		_ = client.Disconnect(ctx)
+	})
+
+	return db
+}
+
+// assertSameFields compara campo a campo para que el fallo indique cuál
+// se perdió.
+func assertSameFields(t *testing.T, want, got any) {
+	t.Helper()
+
+	wv, gv := reflect.ValueOf(want).Elem(), reflect.ValueOf(got).Elem()
+	for i := 0; i < wv.NumField(); i++ {
+		name := wv.Type().Field(i).Name
+		if !reflect.DeepEqual(wv.Field(i).Interface(), gv.Field(i).Interface()) {
+			t.Errorf("campo %s no se persiste: want %#v, got %#v", name, wv.Field(i).Interface(), gv.Field(i).Interface())
+		}
+	}
+	if !t.Failed() && !reflect.DeepEqual(want, got) {
+		t.Errorf("round trip distinto:\nwant %#v\ngot  %#v", want, got)
+	}
+}
+
+// fillValue asigna a v un valor distinto del cero para su tipo, recorriendo
+// structs, punteros y slices. Los instantes se truncan al milisegundo en
+// UTC, que es la precisión con la que BSON guarda las fechas.
+func fillValue(t *testing.T, v reflect.Value, path string) {
+	t.Helper()
+
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		seed := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+		v.Set(reflect.ValueOf(seed.Add(time.Duration(len(path)) * time.Millisecond)))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(path)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(len(path)))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Float64:
+		v.SetFloat(float64(len(path)) / 2)
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillValue(t, v.Elem(), path)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillValue(t, v.Index(0), path+"[0]")
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			fillValue(t, v.Field(i), path+"."+v.Type().Field(i).Name)
+		}
+	default:
+		t.Fatalf("fillValue: tipo %s no soportado en %s; amplía el helper", v.Type(), path)
+	}
+
+	if v.IsZero() {
+		t.Fatalf("fillValue: %s quedó con su valor cero", path)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStreakRepository implementa StreakRepository sobre la colección
+// "user_streaks", con un documento por usuario.
+type MongoStreakRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoStreakRepository construye el repositorio de rachas.
+func NewMongoStreakRepository(db *mongo.Database) *MongoStreakRepository {
+	return &MongoStreakRepository{
+		col: db.Collection("user_streaks"),
+	}
+}
+
+type mongoStreak struct {
+	UserID           string    `bson:"user_id"`
+	Current          int       `bson:"current"`
+	Longest          int       `bson:"longest"`
+	LastQualifiedDay string    `bson:"last_qualified_day,omitempty"`
+	UpdatedAt        time.Time `bson:"updated_at"`
+}
+
+// GetStreak recupera la racha del usuario.
+func (r *MongoStreakRepository) GetStreak(userID string) (*domain.UserStreak, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var doc mongoStreak
+	err := r.col.FindOne(ctx, bson.M{"user_id": userID}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserStreak{
+		UserID:           doc.UserID,
+		Current:          doc.Current,
+		Longest:          doc.Longest,
+		LastQualifiedDay: doc.LastQualifiedDay,
+		UpdatedAt:        doc.UpdatedAt,
+	}, nil
+}
+
+// SaveStreak crea o reemplaza la racha del usuario.
+func (r *MongoStreakRepository) SaveStreak(s *domain.UserStreak) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.col.ReplaceOne(ctx,
+		bson.M{"user_id": s.UserID},
+		mongoStreak{
+			UserID:           s.UserID,
+			Current:          s.Current,
+			Longest:          s.Longest,
+			LastQualifiedDay: s.LastQualifiedDay,
+			UpdatedAt:        s.UpdatedAt,
+		},
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoTagRepository implementa TagRepository sobre la colección "tags".
+type MongoTagRepository struct {
+	col *mongo.Collection
+}
+
+// NewMongoTagRepository construye el repositorio de etiquetas.
+func NewMongoTagRepository(db *mongo.Database) *MongoTagRepository {
+	return &MongoTagRepository{
+		col: db.Collection("tags"),
+	}
+}
+
+// -----------------------------
+// Mongo DTO
+// -----------------------------
+
+type mongoTag struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty"`
+	UserID string             `bson:"user_id"`
+	Name   string             `bson:"name"`
+	Color  string             `bson:"color"`
+
+	CreatedAt time.Time `bson:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// EnsureIndexes crea los índices de la colección: el nombre de una
+// etiqueta es único por usuario.
+func (r *MongoTagRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "name", Value: 1}},
+		Options: options.Index().SetName("uniq_tag_name_per_user").SetUnique(true),
+	})
+	return err
+}
+
+// -----------------------------
+// CREATE / UPDATE / DELETE
+// -----------------------------
+
+func (r *MongoTagRepository) Create(t *domain.Tag) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := r.col.InsertOne(ctx, domainToMongoTag(t))
+	if err != nil {
+		return translateTagWriteError(err)
+	}
+
+	if oid, ok := res.InsertedID.(primitive.ObjectID); ok {
+		t.ID = oid.Hex()
+	}
+
+	return nil
+}
+
+func (r *MongoTagRepository) Update(t *domain.Tag) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(t.ID)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.col.ReplaceOne(ctx, bson.M{"_id": oid}, domainToMongoTag(t))
+	if err != nil {
+		return translateTagWriteError(err)
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+func (r *MongoTagRepository) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.col.DeleteOne(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// -----------------------------
+// FIND
+// -----------------------------
+
+func (r *MongoTagRepository) FindByID(id string) (*domain.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc mongoTag
+	if err := r.col.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return mongoToDomainTag(&doc), nil
+}
+
+// FindByUser devuelve las etiquetas del usuario ordenadas por nombre.
+func (r *MongoTagRepository) FindByUser(userID string) ([]*domain.Tag, error) {
+	return r.find(bson.M{"user_id": userID})
+}
+
+// FindByIDs ignora los IDs mal formados: no pueden corresponder a ninguna
+// etiqueta del usuario.
+func (r *MongoTagRepository) FindByIDs(userID string, ids []string) ([]*domain.Tag, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+
+	return r.find(bson.M{"user_id": userID, "_id": bson.M{"$in": oids}})
+}
+
+func (r *MongoTagRepository) find(filter bson.M) ([]*domain.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tags := []*domain.Tag{}
+	for cursor.Next(ctx) {
+		var doc mongoTag
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tags = append(tags, mongoToDomainTag(&doc))
+	}
+
+	return tags, cursor.Err()
+}
+
+// -----------------------------
+// MAPPERS
+// -----------------------------
+
+func translateTagWriteError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrTagNameTaken
+	}
+	return err
+}
+
+func domainToMongoTag(t *domain.Tag) *mongoTag {
+	return &mongoTag{
+		UserID:    t.UserID,
+		Name:      t.Name,
+		Color:     t.Color,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func mongoToDomainTag(m *mongoTag) *domain.Tag {
+	id := ""
+	if !m.ID.IsZero() {
+		id = m.ID.Hex()
+	}
+
+	return &domain.Tag{
+		ID:        id,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		Color:     m.Color,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoTaskRepository struct {
@@ -32,15 +33,62 @@ type mongoTask struct {
 	Description string             `bson:"description,omitempty"`
 	ProjectID   *string            `bson:"project_id,omitempty"`
 
+	ParentID    *string  `bson:"parent_id,omitempty"`
+	AncestorIDs []string `bson:"ancestor_ids,omitempty"`
+
+	TagIDs []string `bson:"tag_ids,omitempty"`
+
 	Status      string     `bson:"status"`
 	Completed   bool       `bson:"completed"`
 	CompletedAt *time.Time `bson:"completed_at,omitempty"`
 
-	PomodorosCompleted int `bson:"pomodoros_completed"`
-	TotalFocusMinutes  int `bson:"total_focus_minutes"`
+	EstimatedPomodoros *int `bson:"estimated_pomodoros,omitempty"`
+	PomodorosCompleted int  `bson:"pomodoros_completed"`
+	TotalFocusMinutes  int  `bson:"total_focus_minutes"`
+
+	// Ausentes en las tareas guardadas antes de la jerarquía; ver ownMetric
+	OwnPomodoros    *int `bson:"own_pomodoros,omitempty"`
+	OwnFocusMinutes *int `bson:"own_focus_minutes,omitempty"`
+
+	Checklist    []mongoChecklistItem `bson:"checklist,omitempty"`
+	AutoComplete bool                 `bson:"auto_complete,omitempty"`
 
 	CreatedAt time.Time `bson:"created_at"`
 	UpdatedAt time.Time `bson:"updated_at"`
+
+	Version int64 `bson:"version"`
+}
+
+type mongoChecklistItem struct {
+	ID        string     `bson:"id"`
+	Title     string     `bson:"title"`
+	Done      bool       `bson:"done"`
+	DoneAt    *time.Time `bson:"done_at,omitempty"`
+	CreatedAt time.Time  `bson:"created_at"`
+}
+
+// EnsureIndexes crea los índices de la jerarquía (hijos directos por
+// parent_id y subárboles por la ruta materializada) y del filtro por
+// etiquetas.
+func (r *MongoTaskRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.col.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}},
+			Options: options.Index().SetName("parent_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "ancestor_ids", Value: 1}},
+			Options: options.Index().SetName("ancestor_ids"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "tag_ids", Value: 1}},
+			Options: options.Index().SetName("user_tags"),
+		},
+	})
+	return err
 }
 
 // -----------------------------
@@ -51,6 +99,7 @@ func (r *MongoTaskRepository) Create(t *domain.Task) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	t.Version = 1
 	doc := domainToMongoTask(t)
 
 	res, err := r.col.InsertOne(ctx, doc)
@@ -79,8 +128,23 @@ func (r *MongoTaskRepository) Update(t *domain.Task) error {
 	}
 
 	doc := domainToMongoTask(t)
-	_, err = r.col.ReplaceOne(ctx, bson.M{"_id": oid}, doc)
-	return err
+	doc.Version = t.Version + 1
+
+	res, err := r.col.ReplaceOne(ctx, versionedFilter(oid, t.Version), doc)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return missingOrConflict(ctx, r.col, oid, &domain.VersionConflictError{
+			Entity:  "task",
+			ID:      t.ID,
+			Version: t.Version,
+		})
+	}
+
+	t.Version = doc.Version
+	return nil
 }
 
 // -----------------------------
@@ -148,6 +212,67 @@ func (r *MongoTaskRepository) FindByUser(userID string) ([]*domain.Task, error)
 	return tasks, nil
 }
 
+// -----------------------------
+// FIND BY TAGS
+// -----------------------------
+
+func (r *MongoTaskRepository) FindByTags(userID string, tagIDs []string) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "tag_ids": bson.M{"$all": tagIDs}}
+	cursor, err := r.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*domain.Task{}
+	for cursor.Next(ctx) {
+		var doc mongoTask
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, mongoToDomainTask(&doc))
+	}
+
+	return tasks, cursor.Err()
+}
+
+// -----------------------------
+// FIND ESTIMATED COMPLETED
+// -----------------------------
+
+func (r *MongoTaskRepository) FindEstimatedCompleted(userID string, from time.Time) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"user_id":             userID,
+		"completed":           true,
+		"completed_at":        bson.M{"$gte": from},
+		"estimated_pomodoros": bson.M{"$gt": 0},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "completed_at", Value: 1}})
+
+	cursor, err := r.col.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*domain.Task{}
+	for cursor.Next(ctx) {
+		var doc mongoTask
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, mongoToDomainTask(&doc))
+	}
+
+	return tasks, cursor.Err()
+}
+
 // -----------------------------
 // MÉTODOS NUEVOS
 // -----------------------------
@@ -163,15 +288,29 @@ func (r *MongoTaskRepository) UpdateStatus(id string, status domain.TaskStatus)
 
 	_, err = r.col.UpdateOne(ctx,
 		bson.M{"_id": oid},
-		bson.M{"$set": bson.M{
-			"status":     string(status),
-			"updated_at": time.Now(),
-		}},
+		bson.M{
+			"$set": bson.M{
+				"status":     string(status),
+				"updated_at": time.Now(),
+			},
+			"$inc": bson.M{"version": 1},
+		},
 	)
 	return err
 }
 
 func (r *MongoTaskRepository) AddRealMinutes(id string, minutes int) error {
+	return r.incrementWithAncestors(id, 0, minutes)
+}
+
+func (r *MongoTaskRepository) IncrementPomodoroCount(id string) error {
+	return r.incrementWithAncestors(id, 1, 0)
+}
+
+// incrementWithAncestors suma los deltas a la tarea y a todos sus
+// ancestros, de modo que las métricas de un padre acumulan las de sus
+// hijos; los contadores propios solo cambian en la tarea.
+func (r *MongoTaskRepository) incrementWithAncestors(id string, pomodoros, minutes int) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -180,50 +319,353 @@ func (r *MongoTaskRepository) AddRealMinutes(id string, minutes int) error {
 		return err
 	}
 
-	_, err = r.col.UpdateOne(ctx,
-		bson.M{"_id": oid},
-		bson.M{"$inc": bson.M{
-			"total_focus_minutes": minutes,
-		}},
+	var doc struct {
+		AncestorIDs []string `bson:"ancestor_ids"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"ancestor_ids": 1})
+	if err := r.col.FindOne(ctx, bson.M{"_id": oid}, opts).Decode(&doc); err != nil {
+		return err
+	}
+
+	oids, err := toObjectIDs(append(doc.AncestorIDs, id))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.col.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": oids}},
+		metricsUpdate(oid, 1, pomodoros, minutes),
 	)
 	return err
 }
 
-func (r *MongoTaskRepository) IncrementPomodoroCount(id string) error {
+// metricsUpdate construye el pipeline que suma los deltas, multiplicados
+// por sign (un número o una expresión evaluada en cada documento), a los
+// acumulados de cada tarea y, solo en la tarea own, a sus contadores
+// propios. Una tarea sin contadores propios es anterior a la jerarquía y
+// no tenía subtareas: se materializan con el acumulado previo a esta
+// escritura (todas las expresiones de $set leen el documento original).
+func metricsUpdate(own primitive.ObjectID, sign any, pomodoros, minutes int) mongo.Pipeline {
+	isOwn := bson.M{"$eq": bson.A{"$_id", own}}
+	add := func(field string, delta any) bson.M {
+		return bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$" + field, 0}}, delta}}
+	}
+	signed := func(delta int) bson.M {
+		return bson.M{"$multiply": bson.A{sign, delta}}
+	}
+	addOwn := func(field, total string, delta int) bson.M {
+		current := bson.M{"$ifNull": bson.A{"$" + field, bson.M{"$ifNull": bson.A{"$" + total, 0}}}}
+		return bson.M{"$add": bson.A{current, bson.M{"$cond": bson.A{isOwn, signed(delta), 0}}}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"pomodoros_completed": add("pomodoros_completed", signed(pomodoros)),
+			"total_focus_minutes": add("total_focus_minutes", signed(minutes)),
+			"own_pomodoros":       addOwn("own_pomodoros", "pomodoros_completed", pomodoros),
+			"own_focus_minutes":   addOwn("own_focus_minutes", "total_focus_minutes", minutes),
+			"version":             add("version", 1),
+		}}},
+	}
+}
+
+// -----------------------------
+// JERARQUÍA
+// -----------------------------
+
+func (r *MongoTaskRepository) FindChildren(parentID string) ([]*domain.Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.col.Find(ctx, bson.M{"parent_id": parentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []*domain.Task{}
+	for cursor.Next(ctx) {
+		var doc mongoTask
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, mongoToDomainTask(&doc))
+	}
+
+	return tasks, cursor.Err()
+}
+
+// MoveDescendants sustituye, en cada descendiente, el tramo de la ruta
+// anterior a id por path. Usa un pipeline de actualización para hacerlo en
+// una sola escritura sobre todo el subárbol. La propia tarea se excluye
+// aunque una carrera la haya dejado en su ruta, para que deshacer el
+// movimiento pueda repararla.
+func (r *MongoTaskRepository) MoveDescendants(id string, path []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	if path == nil {
+		path = []string{}
+	}
+
+	// Los descendientes conservan el tramo posterior a id
+	tail := bson.M{"$slice": bson.A{
+		"$ancestor_ids",
+		bson.M{"$add": bson.A{bson.M{"$indexOfArray": bson.A{"$ancestor_ids", id}}, 1}},
+		bson.M{"$size": "$ancestor_ids"},
+	}}
+
+	_, err = r.col.UpdateMany(ctx,
+		bson.M{"ancestor_ids": id, "_id": bson.M{"$ne": oid}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"ancestor_ids": bson.M{"$concatArrays": bson.A{path, tail}},
+				"updated_at":   time.Now(),
+				"version":      bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+			}}},
+		},
+	)
+	return err
+}
+
+func (r *MongoTaskRepository) AddMetrics(ids []string, pomodoros, minutes int) error {
+	if len(ids) == 0 || (pomodoros == 0 && minutes == 0) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	oids, err := toObjectIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.col.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": oids}},
+		metricsUpdate(primitive.NilObjectID, 1, pomodoros, minutes),
+	)
+	return err
+}
+
+// MoveMetrics traslada los acumulados de una rama a otra con un único
+// UpdateMany: los ancestros que solo están en from restan, los que solo
+// están en to suman y los comunes a ambas rutas no se tocan.
+func (r *MongoTaskRepository) MoveMetrics(from, to []string, pomodoros, minutes int) error {
+	if pomodoros == 0 && minutes == 0 {
+		return nil
+	}
+
+	inFrom := make(map[string]bool, len(from))
+	for _, id := range from {
+		inFrom[id] = true
+	}
+	inTo := make(map[string]bool, len(to))
+	for _, id := range to {
+		inTo[id] = true
+	}
+
+	var changed, gaining []string
+	for _, id := range from {
+		if !inTo[id] {
+			changed = append(changed, id)
+		}
+	}
+	for _, id := range to {
+		if !inFrom[id] {
+			changed = append(changed, id)
+			gaining = append(gaining, id)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	oids, err := toObjectIDs(changed)
+	if err != nil {
+		return err
+	}
+	gainingOIDs, err := toObjectIDs(gaining)
+	if err != nil {
+		return err
+	}
+
+	sign := bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$_id", gainingOIDs}}, 1, -1}}
+	_, err = r.col.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": oids}},
+		metricsUpdate(primitive.NilObjectID, sign, pomodoros, minutes),
+	)
+	return err
+}
+
+// RecomputeMetrics rehace los acumulados de cada tarea a partir de los
+// contadores propios de su subárbol. Cada tarea se escribe condicionada a
+// la versión leída antes de sumar, y se repite si un incremento la cambió
+// entretanto, para no pisarlo.
+func (r *MongoTaskRepository) RecomputeMetrics(ids []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return err
+		}
+
+		for {
+			var current struct {
+				Version int64 `bson:"version"`
+			}
+			opts := options.FindOne().SetProjection(bson.M{"version": 1})
+			if err := r.col.FindOne(ctx, bson.M{"_id": oid}, opts).Decode(&current); err != nil {
+				return err
+			}
+
+			pomodoros, minutes, err := r.sumOwnMetrics(ctx, oid, id)
+			if err != nil {
+				return err
+			}
+
+			res, err := r.col.UpdateOne(ctx,
+				versionedFilter(oid, current.Version),
+				bson.M{
+					"$set": bson.M{
+						"pomodoros_completed": pomodoros,
+						"total_focus_minutes": minutes,
+					},
+					"$inc": bson.M{"version": 1},
+				},
+			)
+			if err != nil {
+				return err
+			}
+			if res.MatchedCount > 0 {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// sumOwnMetrics suma los contadores propios de la tarea y sus
+// descendientes. Los documentos anteriores a la jerarquía no los tienen y
+// aportan su acumulado (ver ownMetric).
+func (r *MongoTaskRepository) sumOwnMetrics(ctx context.Context, oid primitive.ObjectID, id string) (pomodoros, minutes int, err error) {
+	cursor, err := r.col.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"$or": bson.A{
+			bson.M{"_id": oid},
+			bson.M{"ancestor_ids": id},
+		}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":       nil,
+			"pomodoros": bson.M{"$sum": bson.M{"$ifNull": bson.A{"$own_pomodoros", "$pomodoros_completed"}}},
+			"minutes":   bson.M{"$sum": bson.M{"$ifNull": bson.A{"$own_focus_minutes", "$total_focus_minutes"}}},
+		}}},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals struct {
+		Pomodoros int `bson:"pomodoros"`
+		Minutes   int `bson:"minutes"`
+	}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&totals); err != nil {
+			return 0, 0, err
+		}
+	}
+	return totals.Pomodoros, totals.Minutes, cursor.Err()
+}
+
+func (r *MongoTaskRepository) DeleteSubtree(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.col.UpdateOne(ctx,
+	_, err = r.col.DeleteMany(ctx, bson.M{"$or": bson.A{
 		bson.M{"_id": oid},
-		bson.M{"$inc": bson.M{
-			"pomodoros_completed": 1,
-		}},
+		bson.M{"ancestor_ids": id},
+	}})
+	return err
+}
+
+// -----------------------------
+// ETIQUETAS
+// -----------------------------
+
+func (r *MongoTaskRepository) RemoveTag(tagID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.col.UpdateMany(ctx,
+		bson.M{"tag_ids": tagID},
+		bson.M{
+			"$pull": bson.M{"tag_ids": tagID},
+			"$set":  bson.M{"updated_at": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
 	)
 	return err
 }
 
+func toObjectIDs(ids []string) ([]primitive.ObjectID, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, err
+		}
+		oids = append(oids, oid)
+	}
+	return oids, nil
+}
+
 // -----------------------------
 // MAPPERS
 // -----------------------------
 
 func domainToMongoTask(t *domain.Task) *mongoTask {
+	checklist := make([]mongoChecklistItem, 0, len(t.Checklist))
+	for _, item := range t.Checklist {
+		checklist = append(checklist, mongoChecklistItem(item))
+	}
+
 	return &mongoTask{
 		UserID:             t.UserID,
 		Title:              t.Title,
 		Description:        t.Description,
 		ProjectID:          t.ProjectID,
+		ParentID:           t.ParentID,
+		AncestorIDs:        t.AncestorIDs,
+		TagIDs:             t.TagIDs,
 		Status:             string(t.Status),
 		Completed:          t.Completed,
 		CompletedAt:        t.CompletedAt,
+		EstimatedPomodoros: t.EstimatedPomodoros,
 		PomodorosCompleted: t.PomodorosCompleted,
 		TotalFocusMinutes:  t.TotalFocusMinutes,
+		OwnPomodoros:       &t.OwnPomodoros,
+		OwnFocusMinutes:    &t.OwnFocusMinutes,
+		Checklist:          checklist,
+		AutoComplete:       t.AutoComplete,
 		CreatedAt:          t.CreatedAt,
 		UpdatedAt:          t.UpdatedAt,
+		Version:            t.Version,
 	}
 }
 
@@ -233,18 +675,47 @@ func mongoToDomainTask(m *mongoTask) *domain.Task {
 		id = m.ID.Hex()
 	}
 
+	// Las tareas sin etiquetas se exponen con lista vacía, no null
+	tagIDs := m.TagIDs
+	if tagIDs == nil {
+		tagIDs = []string{}
+	}
+
+	checklist := make([]domain.ChecklistItem, 0, len(m.Checklist))
+	for _, item := range m.Checklist {
+		checklist = append(checklist, domain.ChecklistItem(item))
+	}
+
 	return &domain.Task{
 		ID:                 id,
 		UserID:             m.UserID,
 		Title:              m.Title,
 		Description:        m.Description,
 		ProjectID:          m.ProjectID,
+		ParentID:           m.ParentID,
+		AncestorIDs:        m.AncestorIDs,
+		TagIDs:             tagIDs,
 		Status:             domain.TaskStatus(m.Status),
 		Completed:          m.Completed,
 		CompletedAt:        m.CompletedAt,
+		EstimatedPomodoros: m.EstimatedPomodoros,
 		PomodorosCompleted: m.PomodorosCompleted,
 		TotalFocusMinutes:  m.TotalFocusMinutes,
+		OwnPomodoros:       ownMetric(m.OwnPomodoros, m.PomodorosCompleted),
+		OwnFocusMinutes:    ownMetric(m.OwnFocusMinutes, m.TotalFocusMinutes),
+		Checklist:          checklist,
+		AutoComplete:       m.AutoComplete,
 		CreatedAt:          m.CreatedAt,
 		UpdatedAt:          m.UpdatedAt,
+		Version:            m.Version,
+	}
+}
+
+// ownMetric devuelve el contador propio guardado o, en las tareas
+// anteriores a la jerarquía, el acumulado: sin subtareas, coinciden.
+func ownMetric(own *int, total int) int {
+	if own != nil {
+		return *own
 	}
+	return total
 }
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// versionedFilter selecciona el documento por _id solo si conserva la
+// versión indicada. Los documentos previos al versionado no tienen el
+// campo y se tratan como versión 0.
+func versionedFilter(oid primitive.ObjectID, version int64) bson.M {
+	if version == 0 {
+		return bson.M{
+			"_id": oid,
+			"$or": bson.A{
+				bson.M{"version": 0},
+				bson.M{"version": bson.M{"$exists": false}},
+			},
+		}
+	}
+	return bson.M{"_id": oid, "version": version}
+}
+
+// missingOrConflict distingue, tras una escritura condicional sin
+// coincidencias, si el documento no existe (mongo.ErrNoDocuments) o si
+// cambió de versión (conflict).
+func missingOrConflict(ctx context.Context, col *mongo.Collection, oid primitive.ObjectID, conflict error) error {
+	n, err := col.CountDocuments(ctx, bson.M{"_id": oid})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return conflict
+}
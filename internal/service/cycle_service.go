@@ -6,7 +6,8 @@ import (
 	"pomodoro-backend/internal/domain"
 )
 
-// CycleService maneja la lógica de negocio para ciclos pomodoro.
+// CycleService expone la consulta de ciclos pomodoro. Los ciclos los
+// registra SessionService al completarse cada focus.
 type CycleService struct {
 	repo domain.CycleRepository
 }
@@ -16,22 +17,13 @@ func NewCycleService(repo domain.CycleRepository) *CycleService {
 	return &CycleService{repo: repo}
 }
 
-// RegisterCycle guarda un ciclo terminado.
-func (s *CycleService) RegisterCycle(userID string, taskID string, duration int, startedAt time.Time, finishedAt time.Time, breakUsed bool) error {
-	cycle := &domain.PomodoroCycle{
-		ID:         GenerateID(),
-		UserID:     userID,
-		TaskID:     taskID,
-		Duration:   duration,
-		StartedAt:  startedAt,
-		FinishedAt: finishedAt,
-		BreakUsed:  breakUsed,
-	}
-
-	return s.repo.Save(cycle)
-}
-
 // GetCyclesByTask devuelve los ciclos anteriores de una tarea.
 func (s *CycleService) GetCyclesByTask(taskID string) ([]*domain.PomodoroCycle, error) {
 	return s.repo.GetByTask(taskID)
 }
+
+// GetCyclesByUser devuelve los ciclos de un usuario, opcionalmente acotados
+// a un rango de fechas de finalización.
+func (s *CycleService) GetCyclesByUser(userID string, from, to *time.Time) ([]*domain.PomodoroCycle, error) {
+	return s.repo.GetByUser(userID, from, to)
+}
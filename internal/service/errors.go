@@ -1,14 +1,49 @@
 package service
 
-import "errors"
+import (
+	"errors"
+
+	"pomodoro-backend/internal/domain"
+)
 
 // Errores estándar del dominio de servicio
 var (
 	// Sesiones
-	ErrSessionNotFound        = errors.New("session not found")
-	ErrInvalidState           = errors.New("invalid session state")
-	ErrInvalidStateTransition = errors.New("invalid state transition")
+	ErrSessionNotFound = errors.New("session not found")
+	ErrInvalidState    = errors.New("invalid session state")
+	ErrCadenceNotFound = errors.New("break cadence not configured")
+	ErrInvalidCursor   = errors.New("invalid pagination cursor")
+	ErrNoActiveSession = errors.New("user has no active session")
+
+	// Provienen del dominio o del repositorio, por eso se reexportan
+	ErrInvalidStateTransition = domain.ErrInvalidTransition
+	ErrInvalidPhaseAdjustment = domain.ErrInvalidPhaseAdjustment
+	ErrActiveSessionExists    = domain.ErrActiveSessionExists
+	ErrVersionConflict        = domain.ErrVersionConflict
+	ErrPresetNameTaken        = domain.ErrPresetNameTaken
+	ErrDefaultPresetTaken     = domain.ErrDefaultPresetTaken
+	ErrTagNameTaken           = domain.ErrTagNameTaken
 
 	// Tareas
-	ErrTaskNotFound = errors.New("task not found")
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrParentTaskNotFound    = errors.New("parent task not found")
+	ErrTaskCycle             = domain.ErrTaskCycle
+	ErrTaskHasChildren       = domain.ErrTaskHasChildren
+	ErrChecklistItemNotFound = domain.ErrChecklistItemNotFound
+	ErrChecklistFull         = domain.ErrChecklistFull
+	ErrInvalidChecklistOrder = domain.ErrInvalidChecklistOrder
+
+	// Presets
+	ErrPresetNotFound = errors.New("preset not found")
+
+	// Etiquetas
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTooManyTags = domain.ErrTooManyTags
+
+	// Preferencias
+	ErrInvalidTimezone = errors.New("invalid IANA timezone")
+
+	// Informes
+	ErrInvalidReportPeriod = errors.New("invalid report period")
+	ErrInvalidReportRange  = errors.New("invalid report range")
 )
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// GoalService calcula el progreso de los objetivos de foco de un usuario
+// a partir de las sesiones terminadas.
+type GoalService struct {
+	sessionRepo domain.SessionRepository
+	prefsRepo   domain.PreferencesRepository
+}
+
+// NewGoalService crea el servicio.
+func NewGoalService(sr domain.SessionRepository, pr domain.PreferencesRepository) *GoalService {
+	return &GoalService{sessionRepo: sr, prefsRepo: pr}
+}
+
+// GoalPeriodProgress es el avance de un objetivo en un periodo local del
+// usuario [From, To).
+type GoalPeriodProgress struct {
+	Goal      domain.FocusGoal `json:"goal"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Pomodoros int              `json:"pomodoros"`
+	Minutes   int              `json:"focus_minutes"`
+	Achieved  int              `json:"achieved"` // En la unidad del objetivo
+	Percent   int              `json:"percent"`
+	Completed bool             `json:"completed"`
+}
+
+// GoalProgress agrupa el avance diario y semanal. Un objetivo sin
+// configurar se omite.
+type GoalProgress struct {
+	UserID   string              `json:"user_id"`
+	Timezone string              `json:"timezone"`
+	Daily    *GoalPeriodProgress `json:"daily,omitempty"`
+	Weekly   *GoalPeriodProgress `json:"weekly,omitempty"`
+}
+
+// GetGoals devuelve los objetivos configurados por el usuario.
+func (s *GoalService) GetGoals(userID string) (daily, weekly *domain.FocusGoal, err error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prefs.DailyGoal, prefs.WeeklyGoal, nil
+}
+
+// SetGoals reemplaza los objetivos del usuario sin tocar el resto de sus
+// preferencias. Un objetivo nil queda sin configurar. La escritura solo
+// afecta a los objetivos, para no pisar una cadencia o unas preferencias
+// guardadas a la vez.
+func (s *GoalService) SetGoals(userID string, daily, weekly *domain.FocusGoal) error {
+	return s.prefsRepo.SaveGoals(userID, daily, weekly)
+}
+
+// GetProgress calcula el avance de los objetivos en el día y la semana
+// locales del usuario que contienen at.
+func (s *GoalService) GetProgress(userID string, at time.Time) (*GoalProgress, error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &GoalProgress{UserID: userID, Timezone: prefs.Timezone}
+
+	if prefs.DailyGoal != nil {
+		from, to := prefs.DayRange(at)
+		if progress.Daily, err = s.periodProgress(userID, *prefs.DailyGoal, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	if prefs.WeeklyGoal != nil {
+		from, to := prefs.WeekRange(at)
+		if progress.Weekly, err = s.periodProgress(userID, *prefs.WeeklyGoal, from, to); err != nil {
+			return nil, err
+		}
+	}
+
+	return progress, nil
+}
+
+// periodProgress totaliza los focus terminados en [from, to) y los compara
+// con el objetivo.
+func (s *GoalService) periodProgress(userID string, goal domain.FocusGoal, from, to time.Time) (*GoalPeriodProgress, error) {
+	totals, err := s.sessionRepo.SumFocus(userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &GoalPeriodProgress{
+		Goal:      goal,
+		From:      from,
+		To:        to,
+		Pomodoros: totals.Pomodoros,
+		Minutes:   totals.FocusedSeconds / 60,
+	}
+
+	p.Achieved = goal.Achieved(*totals)
+	if goal.Target > 0 {
+		p.Percent = p.Achieved * 100 / goal.Target
+	}
+	p.Completed = goal.Met(*totals)
+
+	return p, nil
+}
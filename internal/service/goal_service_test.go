@@ -0,0 +1,109 @@
+package service
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// fakePreferencesStore guarda el documento de preferencias de cada usuario
+// con la semántica de MongoPreferencesRepository: SavePreferences reescribe
+// todos los campos y SaveCadence y SaveGoals solo los suyos. afterLoad, si
+// no es nil, se ejecuta una vez tras la siguiente lectura, para intercalar
+// una escritura concurrente.
+type fakePreferencesStore struct {
+	domain.PreferencesRepository
+
+	mu    sync.Mutex
+	prefs map[string]domain.UserPreferences
+
+	afterLoad func()
+}
+
+func (r *fakePreferencesStore) GetPreferences(userID string) (*domain.UserPreferences, error) {
+	r.mu.Lock()
+	stored, ok := r.prefs[userID]
+	r.mu.Unlock()
+
+	if hook := r.afterLoad; hook != nil {
+		r.afterLoad = nil
+		hook()
+	}
+
+	if !ok {
+		return nil, nil
+	}
+	return &stored, nil
+}
+
+func (r *fakePreferencesStore) SavePreferences(p *domain.UserPreferences) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prefs[p.UserID] = *p
+	return nil
+}
+
+func (r *fakePreferencesStore) SaveCadence(userID string, c *domain.BreakCadence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.stored(userID)
+	p.Cadence = c
+	r.prefs[userID] = p
+	return nil
+}
+
+func (r *fakePreferencesStore) SaveGoals(userID string, daily, weekly *domain.FocusGoal) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := r.stored(userID)
+	p.DailyGoal, p.WeeklyGoal = daily, weekly
+	r.prefs[userID] = p
+	return nil
+}
+
+// stored devuelve el documento del usuario o uno por defecto; requiere mu.
+func (r *fakePreferencesStore) stored(userID string) domain.UserPreferences {
+	if p, ok := r.prefs[userID]; ok {
+		return p
+	}
+	return *domain.DefaultUserPreferences(userID)
+}
+
+// TestSetGoalsKeepsConcurrentCadence intercala una escritura de la
+// cadencia justo después de cualquier lectura de las preferencias: guardar
+// los objetivos no debe deshacerla.
+func TestSetGoalsKeepsConcurrentCadence(t *testing.T) {
+	prefs := domain.DefaultUserPreferences("u1")
+	prefs.Timezone = "Europe/Madrid"
+	repo := &fakePreferencesStore{prefs: map[string]domain.UserPreferences{"u1": *prefs}}
+	svc := NewGoalService(nil, repo)
+
+	cadence := &domain.BreakCadence{ShortBreakMinutes: 5, LongBreakMinutes: 20, PomodorosPerSet: 4}
+	repo.afterLoad = func() {
+		if err := repo.SaveCadence("u1", cadence); err != nil {
+			t.Fatalf("SaveCadence: %v", err)
+		}
+	}
+
+	daily := &domain.FocusGoal{Metric: domain.GoalMetricPomodoros, Target: 8}
+	if err := svc.SetGoals("u1", daily, nil); err != nil {
+		t.Fatalf("SetGoals: %v", err)
+	}
+	if repo.afterLoad != nil {
+		// SetGoals no leyó: la cadencia se escribe ahora, antes de comprobar
+		repo.afterLoad()
+	}
+
+	got := repo.prefs["u1"]
+	if !reflect.DeepEqual(got.Cadence, cadence) {
+		t.Errorf("cadencia = %+v, want %+v", got.Cadence, cadence)
+	}
+	if !reflect.DeepEqual(got.DailyGoal, daily) || got.WeeklyGoal != nil {
+		t.Errorf("objetivos = %+v / %+v, want %+v / nil", got.DailyGoal, got.WeeklyGoal, daily)
+	}
+	if got.Timezone != "Europe/Madrid" {
+		t.Errorf("timezone = %q, want Europe/Madrid", got.Timezone)
+	}
+}
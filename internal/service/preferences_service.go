@@ -0,0 +1,52 @@
+package service
+
+import (
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// PreferencesService gestiona las preferencias personales de cada usuario.
+type PreferencesService struct {
+	repo domain.PreferencesRepository
+}
+
+// NewPreferencesService crea el servicio.
+func NewPreferencesService(r domain.PreferencesRepository) *PreferencesService {
+	return &PreferencesService{repo: r}
+}
+
+// GetPreferences devuelve las preferencias del usuario; si nunca las
+// configuró, devuelve los valores por defecto.
+func (s *PreferencesService) GetPreferences(userID string) (*domain.UserPreferences, error) {
+	return loadPreferences(s.repo, userID)
+}
+
+// UpdatePreferences reemplaza las preferencias del usuario. La zona
+// horaria debe ser un nombre IANA válido.
+func (s *PreferencesService) UpdatePreferences(prefs *domain.UserPreferences) (*domain.UserPreferences, error) {
+	if _, err := time.LoadLocation(prefs.Timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	prefs.UpdatedAt = time.Now()
+
+	if err := s.repo.SavePreferences(prefs); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+// loadPreferences recupera las preferencias del usuario o, si no existen,
+// las preferencias por defecto. Lo comparten los servicios que las usan.
+func loadPreferences(repo domain.PreferencesRepository, userID string) (*domain.UserPreferences, error) {
+	prefs, err := repo.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		return domain.DefaultUserPreferences(userID), nil
+	}
+	return prefs, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// PresetService gestiona los presets de sesión de cada usuario.
+type PresetService struct {
+	repo domain.PresetRepository
+}
+
+// NewPresetService crea el servicio.
+func NewPresetService(r domain.PresetRepository) *PresetService {
+	return &PresetService{repo: r}
+}
+
+//
+// ──────────────────────────────────────────────
+//   CREAR PRESET
+// ──────────────────────────────────────────────
+//
+
+func (s *PresetService) CreatePreset(userID, name string, focusMin, breakMin int, isDefault bool) (*domain.SessionPreset, error) {
+	now := time.Now()
+
+	preset := &domain.SessionPreset{
+		UserID:       userID,
+		Name:         name,
+		FocusMinutes: focusMin,
+		BreakMinutes: breakMin,
+		IsDefault:    isDefault,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	if err := s.clearPreviousDefault(preset); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(preset); err != nil {
+		return nil, err
+	}
+
+	return preset, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   CONSULTAR PRESETS
+// ──────────────────────────────────────────────
+//
+
+func (s *PresetService) GetPreset(id string) (*domain.SessionPreset, error) {
+	preset, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrPresetNotFound
+	}
+	return preset, nil
+}
+
+func (s *PresetService) GetPresetsByUser(userID string) ([]*domain.SessionPreset, error) {
+	return s.repo.FindByUser(userID)
+}
+
+//
+// ──────────────────────────────────────────────
+//   ACTUALIZAR PRESET
+// ──────────────────────────────────────────────
+//
+
+func (s *PresetService) UpdatePreset(id, name string, focusMin, breakMin int, isDefault bool) (*domain.SessionPreset, error) {
+	preset, err := s.GetPreset(id)
+	if err != nil {
+		return nil, err
+	}
+
+	preset.Name = name
+	preset.FocusMinutes = focusMin
+	preset.BreakMinutes = breakMin
+	preset.IsDefault = isDefault
+	preset.UpdatedAt = time.Now()
+
+	if err := s.clearPreviousDefault(preset); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(preset); err != nil {
+		return nil, err
+	}
+
+	return preset, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   ELIMINAR PRESET
+// ──────────────────────────────────────────────
+//
+
+// DeletePreset elimina el preset. Si era el predeterminado, el usuario
+// vuelve a las duraciones por defecto del sistema.
+func (s *PresetService) DeletePreset(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return ErrPresetNotFound
+	}
+	return nil
+}
+
+// clearPreviousDefault desmarca el predeterminado anterior antes de guardar
+// un preset que pasa a serlo. El repositorio garantiza un único
+// predeterminado por usuario: si otra petición marca el suyo entre ambas
+// escrituras, la nuestra falla con ErrDefaultPresetTaken en lugar de dejar
+// dos.
+func (s *PresetService) clearPreviousDefault(preset *domain.SessionPreset) error {
+	if !preset.IsDefault {
+		return nil
+	}
+	return s.repo.ClearDefault(preset.UserID, preset.ID)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// fakePresetRepo guarda los presets en memoria y, como el índice parcial
+// de MongoPresetRepository, rechaza un segundo predeterminado por usuario.
+// afterClear, si no es nil, se ejecuta una vez tras el siguiente
+// ClearDefault, para intercalar una escritura concurrente.
+type fakePresetRepo struct {
+	domain.PresetRepository
+
+	mu      sync.Mutex
+	presets map[string]domain.SessionPreset
+
+	afterClear func()
+}
+
+func newFakePresetRepo(presets ...domain.SessionPreset) *fakePresetRepo {
+	r := &fakePresetRepo{presets: make(map[string]domain.SessionPreset)}
+	for _, p := range presets {
+		r.presets[p.ID] = p
+	}
+	return r
+}
+
+func (r *fakePresetRepo) Create(p *domain.SessionPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p.ID = fmt.Sprintf("p%d", len(r.presets)+1)
+	return r.write(p)
+}
+
+func (r *fakePresetRepo) Update(p *domain.SessionPreset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.presets[p.ID]; !ok {
+		return errors.New("not found")
+	}
+	return r.write(p)
+}
+
+// write aplica la restricción de un único predeterminado; requiere mu.
+func (r *fakePresetRepo) write(p *domain.SessionPreset) error {
+	if p.IsDefault {
+		for id, other := range r.presets {
+			if id != p.ID && other.UserID == p.UserID && other.IsDefault {
+				return domain.ErrDefaultPresetTaken
+			}
+		}
+	}
+	r.presets[p.ID] = *p
+	return nil
+}
+
+func (r *fakePresetRepo) FindByID(id string) (*domain.SessionPreset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.presets[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &p, nil
+}
+
+func (r *fakePresetRepo) ClearDefault(userID, exceptID string) error {
+	r.mu.Lock()
+	for id, p := range r.presets {
+		if id != exceptID && p.UserID == userID && p.IsDefault {
+			p.IsDefault = false
+			r.presets[id] = p
+		}
+	}
+	r.mu.Unlock()
+
+	if hook := r.afterClear; hook != nil {
+		r.afterClear = nil
+		hook()
+	}
+	return nil
+}
+
+// defaults devuelve los IDs de los predeterminados del usuario.
+func (r *fakePresetRepo) defaults(userID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id, p := range r.presets {
+		if p.UserID == userID && p.IsDefault {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestUpdatePresetMovesDefault(t *testing.T) {
+	repo := newFakePresetRepo(
+		domain.SessionPreset{ID: "a", UserID: "u1", Name: "corto", IsDefault: true},
+		domain.SessionPreset{ID: "b", UserID: "u1", Name: "largo"},
+	)
+	svc := NewPresetService(repo)
+
+	if _, err := svc.UpdatePreset("b", "largo", 50, 10, true); err != nil {
+		t.Fatalf("UpdatePreset: %v", err)
+	}
+	if got := repo.defaults("u1"); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("predeterminados = %v, want [b]", got)
+	}
+
+	created, err := svc.CreatePreset("u1", "medio", 30, 5, true)
+	if err != nil {
+		t.Fatalf("CreatePreset: %v", err)
+	}
+	if got := repo.defaults("u1"); len(got) != 1 || got[0] != created.ID {
+		t.Fatalf("predeterminados = %v, want [%s]", got, created.ID)
+	}
+}
+
+// TestUpdatePresetLosesDefaultRace marca otro preset como predeterminado
+// entre el desmarcado y la escritura: la petición rezagada falla y el
+// usuario sigue con un único predeterminado.
+func TestUpdatePresetLosesDefaultRace(t *testing.T) {
+	repo := newFakePresetRepo(
+		domain.SessionPreset{ID: "a", UserID: "u1", Name: "corto", IsDefault: true},
+		domain.SessionPreset{ID: "b", UserID: "u1", Name: "largo"},
+		domain.SessionPreset{ID: "c", UserID: "u1", Name: "medio"},
+	)
+	svc := NewPresetService(repo)
+
+	repo.afterClear = func() {
+		if _, err := svc.UpdatePreset("c", "medio", 30, 5, true); err != nil {
+			t.Fatalf("UpdatePreset concurrente: %v", err)
+		}
+	}
+
+	_, err := svc.UpdatePreset("b", "largo", 50, 10, true)
+	if !errors.Is(err, ErrDefaultPresetTaken) {
+		t.Fatalf("err = %v, want ErrDefaultPresetTaken", err)
+	}
+	if got := repo.defaults("u1"); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("predeterminados = %v, want [c]", got)
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// ReportService genera informes de productividad por periodo, alineados
+// con los días, semanas y meses locales del usuario.
+type ReportService struct {
+	repo        domain.ReportRepository
+	sessionRepo domain.SessionRepository
+	taskRepo    domain.TaskRepository
+	tagRepo     domain.TagRepository
+	prefsRepo   domain.PreferencesRepository
+}
+
+// NewReportService crea el servicio.
+func NewReportService(r domain.ReportRepository, sr domain.SessionRepository, tr domain.TaskRepository, tags domain.TagRepository, pr domain.PreferencesRepository) *ReportService {
+	return &ReportService{repo: r, sessionRepo: sr, taskRepo: tr, tagRepo: tags, prefsRepo: pr}
+}
+
+const (
+	defaultHeatmapDays = 30
+	maxHeatmapDays     = 366
+
+	defaultEstimateWeeks = 12
+	maxEstimateWeeks     = 52
+	estimateTrendWindow  = 4 // Semanas que promedia cada punto de la tendencia
+	estimateTopTasks     = 5
+)
+
+// GetReport devuelve el informe del periodo local del usuario que
+// contiene at. Con tagID, el informe se limita a las tareas con esa
+// etiqueta del usuario.
+func (s *ReportService) GetReport(userID string, period domain.ReportPeriod, at time.Time, tagID string) (*domain.ProductivityReport, error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var from, to time.Time
+	switch period {
+	case domain.ReportPeriodDay:
+		from, to = prefs.DayRange(at)
+	case domain.ReportPeriodWeek:
+		from, to = prefs.WeekRange(at)
+	case domain.ReportPeriodMonth:
+		from, to = prefs.MonthRange(at)
+	default:
+		return nil, ErrInvalidReportPeriod
+	}
+
+	report := &domain.ProductivityReport{
+		UserID:   userID,
+		Period:   period,
+		Timezone: prefs.Timezone,
+		From:     from,
+		To:       to,
+		TagID:    tagID,
+	}
+
+	tags, err := s.tagRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*domain.Tag, len(tags))
+	for _, tag := range tags {
+		byID[tag.ID] = tag
+	}
+	if tagID != "" && byID[tagID] == nil {
+		return nil, ErrTagNotFound
+	}
+
+	if err := s.repo.BuildReport(report); err != nil {
+		return nil, err
+	}
+
+	// Se descartan las etiquetas borradas que aún no se quitaron de sus tareas
+	named := report.Tags[:0]
+	for _, row := range report.Tags {
+		if tag, ok := byID[row.TagID]; ok {
+			row.Name, row.Color = tag.Name, tag.Color
+			named = append(named, row)
+		}
+	}
+	report.Tags = named
+
+	return report, nil
+}
+
+// GetFocusHeatmap calcula el mapa de calor de foco del usuario en
+// [from, to). Sin límites explícitos cubre los últimos 30 días; el rango
+// no puede superar un año.
+func (s *ReportService) GetFocusHeatmap(userID string, from, to *time.Time) (*domain.FocusHeatmap, error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -defaultHeatmapDays)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) || end.Sub(start) > maxHeatmapDays*24*time.Hour {
+		return nil, ErrInvalidReportRange
+	}
+
+	intervals, err := s.sessionRepo.FindFocusIntervals(userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := &domain.FocusHeatmap{
+		UserID:       userID,
+		Timezone:     prefs.Timezone,
+		From:         start,
+		To:           end,
+		WeekStartDay: prefs.WeekStartDay,
+	}
+	heatmap.Fill(intervals, prefs.Location())
+
+	return heatmap, nil
+}
+
+// GetEstimateAccuracy compara lo estimado con lo realizado en las tareas
+// completadas durante las últimas weeks semanas locales (12 si weeks es 0).
+// La tendencia tiene un punto por semana con la precisión móvil de las
+// últimas cuatro semanas.
+func (s *ReportService) GetEstimateAccuracy(userID string, weeks int) (*domain.EstimateAccuracy, error) {
+	if weeks == 0 {
+		weeks = defaultEstimateWeeks
+	}
+	if weeks < 1 || weeks > maxEstimateWeeks {
+		return nil, ErrInvalidReportRange
+	}
+
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inicios de semana, del más antiguo al actual, incluyendo las semanas
+	// previas que necesita la ventana móvil del primer punto.
+	total := weeks + estimateTrendWindow - 1
+	starts := make([]time.Time, total+1)
+	starts[total-1], starts[total] = prefs.WeekRange(time.Now())
+	for i := total - 2; i >= 0; i-- {
+		starts[i], _ = prefs.WeekRange(starts[i+1].Add(-time.Nanosecond))
+	}
+
+	tasks, err := s.taskRepo.FindEstimatedCompleted(userID, starts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	from := starts[estimateTrendWindow-1]
+	report := &domain.EstimateAccuracy{
+		UserID:       userID,
+		Timezone:     prefs.Timezone,
+		From:         from,
+		TopOverruns:  []domain.TaskEstimate{},
+		TopUnderruns: []domain.TaskEstimate{},
+		Trend:        make([]domain.EstimateTrendPoint, 0, weeks),
+	}
+
+	all := make([]domain.TaskEstimate, 0, len(tasks))
+	var inRange []domain.TaskEstimate
+	deviation := 0
+	for _, t := range tasks {
+		e := domain.NewTaskEstimate(t)
+		all = append(all, e)
+		if e.CompletedAt.Before(from) {
+			continue
+		}
+
+		inRange = append(inRange, e)
+		deviation += e.Deviation
+		switch {
+		case e.Deviation > 0:
+			report.Overrun++
+			report.TopOverruns = append(report.TopOverruns, e)
+		case e.Deviation < 0:
+			report.Underrun++
+			report.TopUnderruns = append(report.TopUnderruns, e)
+		default:
+			report.OnTarget++
+		}
+	}
+
+	report.TasksEstimated = len(inRange)
+	report.AccuracyPercent = domain.AccuracyPercentOf(inRange)
+	if len(inRange) > 0 {
+		report.MeanDeviation = float64(deviation) / float64(len(inRange))
+	}
+
+	sort.SliceStable(report.TopOverruns, func(i, j int) bool {
+		return report.TopOverruns[i].Deviation > report.TopOverruns[j].Deviation
+	})
+	sort.SliceStable(report.TopUnderruns, func(i, j int) bool {
+		return report.TopUnderruns[i].Deviation < report.TopUnderruns[j].Deviation
+	})
+	if len(report.TopOverruns) > estimateTopTasks {
+		report.TopOverruns = report.TopOverruns[:estimateTopTasks]
+	}
+	if len(report.TopUnderruns) > estimateTopTasks {
+		report.TopUnderruns = report.TopUnderruns[:estimateTopTasks]
+	}
+
+	// Las tareas llegan ordenadas por CompletedAt, así que cada ventana es
+	// un subrango contiguo de all.
+	for w := estimateTrendWindow - 1; w < total; w++ {
+		windowFrom, weekEnd := starts[w-estimateTrendWindow+1], starts[w+1]
+		lo := sort.Search(len(all), func(i int) bool { return !all[i].CompletedAt.Before(windowFrom) })
+		hi := sort.Search(len(all), func(i int) bool { return !all[i].CompletedAt.Before(weekEnd) })
+
+		report.Trend = append(report.Trend, domain.EstimateTrendPoint{
+			WeekStart:       starts[w],
+			WeekEnd:         weekEnd,
+			Tasks:           hi - lo,
+			AccuracyPercent: domain.AccuracyPercentOf(all[lo:hi]),
+		})
+	}
+
+	return report, nil
+}
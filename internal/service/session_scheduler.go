@@ -0,0 +1,81 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// SessionScheduler mantiene en memoria un temporizador por cada sesión
+// con una fase en curso (focus o break) y ejecuta onExpire cuando vence
+// su deadline. Es la pieza que hace al servidor la fuente de verdad del
+// tiempo: aunque el cliente desaparezca, la sesión avanza de estado.
+type SessionScheduler struct {
+	mu       sync.Mutex
+	timers   map[string]*time.Timer
+	stopped  bool
+	onExpire func(id string)
+}
+
+// NewSessionScheduler construye un scheduler que invocará onExpire con el
+// ID de la sesión cuyo deadline haya vencido.
+func NewSessionScheduler(onExpire func(id string)) *SessionScheduler {
+	return &SessionScheduler{
+		timers:   make(map[string]*time.Timer),
+		onExpire: onExpire,
+	}
+}
+
+// Schedule programa (o reprograma) el vencimiento de una sesión. Un
+// deadline en el pasado se dispara de inmediato.
+func (s *SessionScheduler) Schedule(id string, deadline time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return
+	}
+
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(time.Until(deadline), func() {
+		s.mu.Lock()
+		// Si el timer fue reemplazado o cancelado mientras esperábamos
+		// el lock, no hacemos nada.
+		if s.timers[id] != timer {
+			s.mu.Unlock()
+			return
+		}
+		delete(s.timers, id)
+		s.mu.Unlock()
+
+		s.onExpire(id)
+	})
+	s.timers[id] = timer
+}
+
+// Unschedule cancela el temporizador de una sesión, si existe.
+func (s *SessionScheduler) Unschedule(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[id]; ok {
+		t.Stop()
+		delete(s.timers, id)
+	}
+}
+
+// Stop detiene todos los temporizadores pendientes. Tras llamarlo, el
+// scheduler ignora nuevas programaciones.
+func (s *SessionScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, t := range s.timers {
+		t.Stop()
+		delete(s.timers, id)
+	}
+	s.stopped = true
+}
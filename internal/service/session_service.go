@@ -1,6 +1,8 @@
 package service
 
 import (
+	"errors"
+	"log"
 	"time"
 
 	"pomodoro-backend/internal/domain"
@@ -11,13 +13,225 @@ import (
 type SessionService struct {
 	sessionRepo domain.SessionRepository
 	taskRepo    domain.TaskRepository
+	cycleRepo   domain.CycleRepository
+	prefsRepo   domain.PreferencesRepository
+	presetRepo  domain.PresetRepository
+	machine     *domain.SessionStateMachine
+	scheduler   *SessionScheduler
 }
 
-func NewSessionService(sr domain.SessionRepository, tr domain.TaskRepository) *SessionService {
-	return &SessionService{
+func NewSessionService(
+	sr domain.SessionRepository,
+	tr domain.TaskRepository,
+	cr domain.CycleRepository,
+	prefs domain.PreferencesRepository,
+	pr domain.PresetRepository,
+) *SessionService {
+	s := &SessionService{
 		sessionRepo: sr,
 		taskRepo:    tr,
+		cycleRepo:   cr,
+		prefsRepo:   prefs,
+		presetRepo:  pr,
+		machine:     domain.NewSessionStateMachine(),
 	}
+	s.scheduler = NewSessionScheduler(s.expirePhase)
+
+	// Efectos sobre otras entidades: solo tras persistir la transición
+	s.machine.OnTransition(domain.SessionActionFinish, s.onFocusCompleted)
+	s.machine.OnTransition(domain.SessionActionExpireFocus, s.onFocusCompleted)
+	s.machine.OnTransition(domain.SessionActionFinishBreak, s.onBreakCompleted)
+	s.machine.OnTransition(domain.SessionActionExpireBreak, s.onBreakCompleted)
+
+	return s
+}
+
+// OnTransition permite a otros componentes reaccionar a una acción del
+// ciclo de vida. El hook se ejecuta después de persistir la transición.
+func (s *SessionService) OnTransition(action domain.SessionAction, hook domain.SessionHook) {
+	s.machine.OnTransition(action, hook)
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   TEMPORIZADOR DEL SERVIDOR
+// ─────────────────────────────────────────────────────────────
+//
+
+// RecoverTimers reprograma los deadlines de las sesiones que quedaron en
+// curso antes de un reinicio. Las fases ya vencidas se cierran de inmediato.
+func (s *SessionService) RecoverTimers() error {
+	sessions, err := s.sessionRepo.FindByStates(
+		domain.SessionStateRunning,
+		domain.SessionStateBreakRunning,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		s.syncTimer(session)
+	}
+
+	return nil
+}
+
+// duplicateSessionReason queda como motivo en el evento CANCELLED de las
+// sesiones cerradas por CloseDuplicateActiveSessions.
+const duplicateSessionReason = "duplicate active session closed at startup"
+
+// CloseDuplicateActiveSessions deja a cada usuario con una sola sesión
+// activa, la de StartedAt más reciente, y cancela las demás. Corrige los
+// datos previos a la restricción de sesión activa única y debe ejecutarse
+// antes de crear su índice. Es idempotente; devuelve cuántas canceló.
+func (s *SessionService) CloseDuplicateActiveSessions() (int, error) {
+	sessions, err := s.sessionRepo.FindByStates(domain.ActiveSessionStates...)
+	if err != nil {
+		return 0, err
+	}
+
+	newest := make(map[string]*domain.Session)
+	for _, session := range sessions {
+		if cur, ok := newest[session.UserID]; !ok || session.StartedAt.After(cur.StartedAt) {
+			newest[session.UserID] = session
+		}
+	}
+
+	closed := 0
+	for _, session := range sessions {
+		if newest[session.UserID] == session {
+			continue
+		}
+
+		from := session.State
+		event, err := s.machine.Apply(session, domain.SessionActionCancel, time.Now())
+		if err != nil {
+			return closed, err
+		}
+		event.Reason = duplicateSessionReason
+
+		if _, err := s.commit(session, domain.SessionActionCancel, from); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+
+	return closed, nil
+}
+
+// StopTimers detiene el scheduler interno.
+func (s *SessionService) StopTimers() {
+	s.scheduler.Stop()
+}
+
+// syncTimer alinea el scheduler con el estado persistido de la sesión:
+// toda fase en curso tiene temporizador, incluidas las de sesiones previas
+// a PhaseEndsAt, cuyo deadline se deriva de sus duraciones.
+func (s *SessionService) syncTimer(session *domain.Session) {
+	running := session.State == domain.SessionStateRunning ||
+		session.State == domain.SessionStateBreakRunning
+
+	if deadline := session.EnsurePhaseDeadline(); running && deadline != nil {
+		s.scheduler.Schedule(session.ID, *deadline)
+		return
+	}
+
+	s.scheduler.Unschedule(session.ID)
+}
+
+// expirePhase es invocado por el scheduler cuando vence el deadline de una
+// sesión. Cierra el focus o el break usando el deadline como instante de fin.
+func (s *SessionService) expirePhase(id string) {
+	session, err := s.sessionRepo.FindByID(id)
+	if err != nil {
+		log.Printf("scheduler: no se pudo cargar la sesión %s: %v", id, err)
+		return
+	}
+
+	if session.EnsurePhaseDeadline() == nil {
+		return
+	}
+
+	// El deadline pudo moverse (p. ej. tras reanudar); reprogramamos.
+	if time.Now().Before(*session.PhaseEndsAt) {
+		s.syncTimer(session)
+		return
+	}
+
+	action := domain.SessionActionExpireFocus
+	if session.State == domain.SessionStateBreakRunning {
+		action = domain.SessionActionExpireBreak
+	}
+
+	from := session.State
+	if _, err := s.machine.Apply(session, action, *session.PhaseEndsAt); err != nil {
+		return
+	}
+
+	// Si el usuario actuó a la vez (pausa, fin manual), su transición gana
+	// y la nuestra se descarta sin efectos.
+	if _, err := s.commit(session, action, from); err != nil {
+		if !errors.Is(err, ErrInvalidStateTransition) && !errors.Is(err, domain.ErrVersionConflict) {
+			log.Printf("scheduler: no se pudo cerrar la sesión %s: %v", id, err)
+		}
+		return
+	}
+
+	if action == domain.SessionActionExpireFocus {
+		s.autoStartBreak(session)
+	}
+}
+
+// loadSession recupera la sesión y valida la precondición de versión del
+// cliente (If-Match). expectedVersion 0 significa sin precondición.
+func (s *SessionService) loadSession(id string, expectedVersion int64) (*domain.Session, error) {
+	session, err := s.sessionRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+
+	if expectedVersion != 0 && session.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{
+			Entity:  "session",
+			ID:      id,
+			Version: expectedVersion,
+		}
+	}
+
+	return session, nil
+}
+
+// transition ejecuta una acción sin datos adicionales: carga la sesión, la
+// hace avanzar en la máquina de estados y persiste el resultado.
+func (s *SessionService) transition(id string, expectedVersion int64, action domain.SessionAction) (*domain.Session, error) {
+	session, err := s.loadSession(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	from := session.State
+	if _, err := s.machine.Apply(session, action, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.commit(session, action, from)
+}
+
+// commit persiste una transición de estado de forma atómica: la base de
+// datos solo la acepta si la sesión sigue en el estado from. Si otra
+// petición concurrente ganó la carrera, devuelve ErrInvalidStateTransition.
+// Los hooks de la acción se ejecutan solo si la escritura tuvo éxito.
+func (s *SessionService) commit(session *domain.Session, action domain.SessionAction, from domain.SessionState) (*domain.Session, error) {
+	if err := s.sessionRepo.TransitionSession(session, from); err != nil {
+		if errors.Is(err, domain.ErrStateConflict) {
+			return nil, ErrInvalidStateTransition
+		}
+		return nil, err
+	}
+
+	s.syncTimer(session)
+	s.machine.RunHooks(action, session, from)
+	return session, nil
 }
 
 //
@@ -26,15 +240,33 @@ func NewSessionService(sr domain.SessionRepository, tr domain.TaskRepository) *S
 // ─────────────────────────────────────────────────────────────
 //
 
+// CreateAndStartSession inicia un nuevo pomodoro. Si el usuario ya tiene
+// una sesión activa se rechaza con ErrActiveSessionExists, salvo que
+// replaceActive sea true: en ese caso la sesión previa se cancela.
 func (s *SessionService) CreateAndStartSession(
 	userID string,
 	projectID *string,
 	taskID *string,
 	focusMin int,
 	breakMin int,
+	replaceActive bool,
 ) (*domain.Session, error) {
 
+	active, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		if !replaceActive {
+			return nil, ErrActiveSessionExists
+		}
+		if _, err := s.CancelSession(active.ID, 0, "replaced by a new session"); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
+	endsAt := now.Add(time.Duration(focusMin) * time.Minute)
 
 	session := &domain.Session{
 		UserID:        userID,
@@ -44,206 +276,573 @@ func (s *SessionService) CreateAndStartSession(
 		BreakMinutes:  breakMin,
 		State:         domain.SessionStateRunning,
 		StartedAt:     now,
+		PhaseEndsAt:   &endsAt,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 		Interruptions: 0,
 	}
+	session.RecordEvent(domain.SessionEventStarted, now, "")
+
+	// Ubicar el pomodoro dentro del set según la cadencia del usuario
+	cadence, err := s.prefsRepo.GetCadence(userID)
+	if err != nil {
+		return nil, err
+	}
+	if cadence != nil {
+		if session.SetPosition, err = s.nextSetPosition(userID, cadence); err != nil {
+			return nil, err
+		}
+	}
 
-	// Guardar la sesión
+	// Guardar la sesión; el índice único cubre la carrera entre la
+	// comprobación anterior y la inserción
 	if err := s.sessionRepo.CreateSession(session); err != nil {
 		return nil, err
 	}
 
-	// Si está ligada a una tarea → ponerla en progreso
+	s.syncTimer(session)
+
+	// Si está ligada a una tarea → ponerla en progreso. La escritura es
+	// atómica para no competir con los $inc de métricas sobre la tarea.
 	if taskID != nil {
-		task, err := s.taskRepo.FindByID(*taskID)
-		if err == nil {
-			task.Status = domain.TaskStatusInProgress
-			task.UpdatedAt = now
-			_ = s.taskRepo.Update(task)
+		if err := s.taskRepo.UpdateStatus(*taskID, domain.TaskStatusInProgress); err != nil {
+			log.Printf("no se pudo poner en progreso la tarea %s: %v", *taskID, err)
 		}
 	}
 
 	return session, nil
 }
 
+// ResolveDurations decide las duraciones de una nueva sesión. Cada valor
+// explícito tiene prioridad; los omitidos se toman, en orden, del preset
+// indicado, del preset predeterminado del usuario o de sus preferencias
+// (que a su vez usan el 25/5 del sistema si no las configuró).
+func (s *SessionService) ResolveDurations(userID string, presetID *string, focusMin, breakMin *int) (int, int, error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	focus, brk := prefs.DefaultFocusMinutes, prefs.DefaultBreakMinutes
+
+	var preset *domain.SessionPreset
+	switch {
+	case presetID != nil:
+		p, err := s.presetRepo.FindByID(*presetID)
+		if err != nil || p.UserID != userID {
+			return 0, 0, ErrPresetNotFound
+		}
+		preset = p
+	case focusMin == nil || breakMin == nil:
+		p, err := s.presetRepo.FindDefault(userID)
+		if err != nil {
+			return 0, 0, err
+		}
+		preset = p
+	}
+
+	if preset != nil {
+		focus, brk = preset.FocusMinutes, preset.BreakMinutes
+	}
+	if focusMin != nil {
+		focus = *focusMin
+	}
+	if breakMin != nil {
+		brk = *breakMin
+	}
+
+	return focus, brk, nil
+}
+
 //
 // ─────────────────────────────────────────────────────────────
 //   PAUSAR SESIÓN
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) PauseSession(id string) (*domain.Session, error) {
-	session, err := s.sessionRepo.FindByID(id)
+// PauseSession pausa el focus en curso. La interrupción (clasificación y
+// motivo) es opcional y queda registrada en la línea de tiempo de la sesión.
+func (s *SessionService) PauseSession(id string, expectedVersion int64, interruption domain.Interruption) (*domain.Session, error) {
+	session, err := s.loadSession(id, expectedVersion)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
 
-	if session.State != domain.SessionStateRunning {
-		return nil, ErrInvalidStateTransition
+	from := session.State
+	event, err := s.machine.Apply(session, domain.SessionActionPause, time.Now())
+	if err != nil {
+		return nil, err
 	}
+	event.Reason = interruption.Reason
+	event.Interruption = interruption.Type
 
-	now := time.Now()
-	session.State = domain.SessionStatePaused
-	session.PausedAt = &now
-	session.UpdatedAt = now
+	return s.commit(session, domain.SessionActionPause, from)
+}
 
-	// Contar interrupciones
-	session.Interruptions++
+//
+// ─────────────────────────────────────────────────────────────
+//   REANUDAR SESIÓN
+// ─────────────────────────────────────────────────────────────
+//
 
-	return session, s.sessionRepo.UpdateSession(session)
+func (s *SessionService) ResumeSession(id string, expectedVersion int64) (*domain.Session, error) {
+	return s.transition(id, expectedVersion, domain.SessionActionResume)
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   REANUDAR SESIÓN
+//   FINALIZAR SESIÓN (FIN DEL FOCUS)
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) ResumeSession(id string) (*domain.Session, error) {
-	session, err := s.sessionRepo.FindByID(id)
+func (s *SessionService) FinishSession(id string, expectedVersion int64) (*domain.Session, error) {
+	session, err := s.transition(id, expectedVersion, domain.SessionActionFinish)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
+	return s.autoStartBreak(session), nil
+}
 
-	if session.State != domain.SessionStatePaused {
-		return nil, ErrInvalidStateTransition
+// autoStartBreak inicia el descanso si el usuario lo tiene configurado en
+// sus preferencias. Un fallo no revierte el fin del focus: se registra y
+// se devuelve la sesión tal como quedó.
+func (s *SessionService) autoStartBreak(session *domain.Session) *domain.Session {
+	prefs, err := loadPreferences(s.prefsRepo, session.UserID)
+	if err != nil {
+		log.Printf("no se pudieron leer las preferencias de %s: %v", session.UserID, err)
+		return session
+	}
+	if !prefs.AutoStartBreak {
+		return session
 	}
 
-	now := time.Now()
-	session.State = domain.SessionStateRunning
-	session.PausedAt = nil
-	session.UpdatedAt = now
+	started, err := s.StartBreak(session.ID, session.Version)
+	if err != nil {
+		log.Printf("no se pudo iniciar el break de la sesión %s: %v", session.ID, err)
+		return session
+	}
+	return started
+}
+
+// onFocusCompleted acredita el focus a la tarea asociada y registra el
+// ciclo completado. Se ejecuta tras persistir el fin del focus.
+func (s *SessionService) onFocusCompleted(session *domain.Session, _ domain.SessionState) {
+	// Tiempo realmente enfocado, redondeado al minuto más cercano
+	focusMinutes := (session.FocusedSeconds + 30) / 60
+
+	// Si está ligada a una tarea → sumamos métrica del focus
+	if session.TaskID != nil {
+		if err := s.taskRepo.AddRealMinutes(*session.TaskID, focusMinutes); err != nil {
+			log.Printf("no se pudieron sumar los minutos de la sesión %s a la tarea %s: %v", session.ID, *session.TaskID, err)
+		}
+		if err := s.taskRepo.IncrementPomodoroCount(*session.TaskID); err != nil {
+			log.Printf("no se pudo sumar el pomodoro de la sesión %s a la tarea %s: %v", session.ID, *session.TaskID, err)
+		}
+	}
 
-	return session, s.sessionRepo.UpdateSession(session)
+	// Registrar el ciclo completado; BreakUsed se marca al cerrar el break
+	cycle := &domain.PomodoroCycle{
+		SessionID:  session.ID,
+		UserID:     session.UserID,
+		Duration:   focusMinutes,
+		StartedAt:  session.StartedAt,
+		FinishedAt: *session.FinishedAt,
+	}
+	if session.TaskID != nil {
+		cycle.TaskID = *session.TaskID
+	}
+	if err := s.cycleRepo.Save(cycle); err != nil {
+		log.Printf("no se pudo registrar el ciclo de la sesión %s: %v", session.ID, err)
+	}
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   FINALIZAR SESIÓN (FIN DEL FOCUS)
+//   INICIAR BREAK
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) FinishSession(id string) (*domain.Session, error) {
-	session, err := s.sessionRepo.FindByID(id)
+func (s *SessionService) StartBreak(id string, expectedVersion int64) (*domain.Session, error) {
+	session, err := s.loadSession(id, expectedVersion)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
 
-	if session.State != domain.SessionStateRunning &&
-		session.State != domain.SessionStatePaused {
+	if !s.machine.Can(session.State, domain.SessionActionStartBreak) {
 		return nil, ErrInvalidStateTransition
 	}
 
-	now := time.Now()
-	session.State = domain.SessionStateFinished
-	session.FinishedAt = &now
-	session.UpdatedAt = now
+	// Con cadencia configurada, la duración del break la decide el set
+	cadence, err := s.prefsRepo.GetCadence(session.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if cadence != nil && session.SetPosition > 0 {
+		session.BreakMinutes, session.LongBreak = cadence.BreakMinutesFor(session.SetPosition)
+	}
 
-	// Si está ligada a una tarea → sumamos métrica del focus
-	if session.TaskID != nil {
-		focusMinutes := session.FocusMinutes
+	from := session.State
+	if _, err := s.machine.Apply(session, domain.SessionActionStartBreak, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.commit(session, domain.SessionActionStartBreak, from)
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   SALTAR BREAK
+// ─────────────────────────────────────────────────────────────
+//
+
+// SkipBreak cierra una sesión cuyo focus terminó sin tomar el descanso.
+// Con startNext, además inicia el siguiente pomodoro con la misma tarea,
+// proyecto y duraciones; next es nil en caso contrario.
+//
+// Antes de saltar se comprueba que el usuario no tenga otra sesión activa,
+// de modo que el caso previsible de fallo no deja el descanso saltado sin
+// siguiente pomodoro. Si aun así la creación falla, el salto ya quedó
+// persistido: se devuelve skipped junto con el error de la creación.
+func (s *SessionService) SkipBreak(id string, expectedVersion int64, startNext bool) (skipped *domain.Session, next *domain.Session, err error) {
+	if startNext {
+		if err := s.ensureCanStartNext(id, expectedVersion); err != nil {
+			return nil, nil, err
+		}
+	}
 
-		_ = s.taskRepo.AddRealMinutes(*session.TaskID, focusMinutes)
-		_ = s.taskRepo.IncrementPomodoroCount(*session.TaskID)
+	skipped, err = s.transition(id, expectedVersion, domain.SessionActionSkipBreak)
+	if err != nil || !startNext {
+		return skipped, nil, err
 	}
 
-	return session, s.sessionRepo.UpdateSession(session)
+	next, err = s.CreateAndStartSession(
+		skipped.UserID,
+		skipped.ProjectID,
+		skipped.TaskID,
+		skipped.FocusMinutes,
+		skipped.BreakMinutes,
+		false,
+	)
+	if err != nil {
+		return skipped, nil, err
+	}
+
+	return skipped, next, nil
+}
+
+// ensureCanStartNext valida, sin escribir nada, que tras saltar el
+// descanso de la sesión se podrá iniciar otro pomodoro.
+func (s *SessionService) ensureCanStartNext(id string, expectedVersion int64) error {
+	session, err := s.loadSession(id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if !s.machine.Can(session.State, domain.SessionActionSkipBreak) {
+		return ErrInvalidStateTransition
+	}
+
+	active, err := s.sessionRepo.FindActiveByUser(session.UserID)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		return ErrActiveSessionExists
+	}
+	return nil
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   INICIAR BREAK
+//   PAUSAR BREAK
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) StartBreak(id string) (*domain.Session, error) {
-	session, err := s.sessionRepo.FindByID(id)
+func (s *SessionService) PauseBreak(id string, expectedVersion int64) (*domain.Session, error) {
+	return s.transition(id, expectedVersion, domain.SessionActionPauseBreak)
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   REANUDAR BREAK
+// ─────────────────────────────────────────────────────────────
+//
+
+func (s *SessionService) ResumeBreak(id string, expectedVersion int64) (*domain.Session, error) {
+	return s.transition(id, expectedVersion, domain.SessionActionResumeBreak)
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   FINALIZAR BREAK
+// ─────────────────────────────────────────────────────────────
+//
+
+func (s *SessionService) FinishBreak(id string, expectedVersion int64) (*domain.Session, error) {
+	return s.transition(id, expectedVersion, domain.SessionActionFinishBreak)
+}
+
+// onBreakCompleted marca como usado el break del ciclo de la sesión.
+func (s *SessionService) onBreakCompleted(session *domain.Session, _ domain.SessionState) {
+	if err := s.cycleRepo.MarkBreakUsed(session.ID); err != nil {
+		log.Printf("no se pudo marcar el break del ciclo de la sesión %s: %v", session.ID, err)
+	}
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   EXTENDER / ACORTAR FASE
+// ─────────────────────────────────────────────────────────────
+//
+
+// ExtendPhase suma minutos a la fase en curso (focus o break).
+func (s *SessionService) ExtendPhase(id string, expectedVersion int64, minutes int) (*domain.Session, error) {
+	return s.adjustPhase(id, expectedVersion, domain.SessionActionExtend, minutes)
+}
+
+// ShortenPhase resta minutos a la fase en curso (focus o break).
+func (s *SessionService) ShortenPhase(id string, expectedVersion int64, minutes int) (*domain.Session, error) {
+	return s.adjustPhase(id, expectedVersion, domain.SessionActionShorten, minutes)
+}
+
+// adjustPhase aplica el ajuste de duración y lo registra en la línea de
+// tiempo; commit reprograma el temporizador con el nuevo deadline.
+func (s *SessionService) adjustPhase(id string, expectedVersion int64, action domain.SessionAction, minutes int) (*domain.Session, error) {
+	session, err := s.loadSession(id, expectedVersion)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
 
-	if session.State != domain.SessionStateFinished {
+	if !s.machine.Can(session.State, action) {
 		return nil, ErrInvalidStateTransition
 	}
 
+	delta := minutes
+	if action == domain.SessionActionShorten {
+		delta = -minutes
+	}
+
 	now := time.Now()
-	session.State = domain.SessionStateBreakRunning
-	session.BreakStartedAt = &now
-	session.UpdatedAt = now
+	if err := session.AdjustPhase(delta, now); err != nil {
+		return nil, err
+	}
 
-	return session, s.sessionRepo.UpdateSession(session)
+	from := session.State
+	event, err := s.machine.Apply(session, action, now)
+	if err != nil {
+		return nil, err
+	}
+	event.Minutes = minutes
+
+	return s.commit(session, action, from)
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   PAUSAR BREAK
+//   TRANSICIONES DISPONIBLES
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) PauseBreak(id string) (*domain.Session, error) {
+// AvailableTransitions describe las acciones que un cliente puede ejecutar
+// sobre una sesión en su estado actual.
+type AvailableTransitions struct {
+	SessionID   string                     `json:"session_id"`
+	State       domain.SessionState        `json:"state"`
+	Version     int64                      `json:"version"`
+	Transitions []domain.SessionTransition `json:"transitions"`
+}
+
+// GetAvailableTransitions consulta la máquina de estados para la sesión.
+func (s *SessionService) GetAvailableTransitions(id string) (*AvailableTransitions, error) {
 	session, err := s.sessionRepo.FindByID(id)
 	if err != nil {
 		return nil, ErrSessionNotFound
 	}
 
-	if session.State != domain.SessionStateBreakRunning {
-		return nil, ErrInvalidStateTransition
+	return &AvailableTransitions{
+		SessionID:   session.ID,
+		State:       session.State,
+		Version:     session.Version,
+		Transitions: s.machine.Available(session.State),
+	}, nil
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   SESIÓN ACTIVA
+// ─────────────────────────────────────────────────────────────
+//
+
+// GetActiveSession devuelve la sesión activa del usuario.
+func (s *SessionService) GetActiveSession(userID string) (*domain.Session, error) {
+	session, err := s.sessionRepo.FindActiveByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrNoActiveSession
 	}
+	return session, nil
+}
 
-	now := time.Now()
-	session.State = domain.SessionStateBreakPaused
-	session.PausedAt = &now
-	session.UpdatedAt = now
+//
+// ─────────────────────────────────────────────────────────────
+//   HISTORIAL DE SESIONES
+// ─────────────────────────────────────────────────────────────
+//
+
+const (
+	defaultSessionPageSize = 20
+	maxSessionPageSize     = 100
+)
+
+// ListSessions consulta el historial de sesiones aplicando los límites de
+// paginación del servicio.
+func (s *SessionService) ListSessions(query domain.SessionQuery) (*domain.SessionPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = defaultSessionPageSize
+	}
+	if query.Limit > maxSessionPageSize {
+		query.Limit = maxSessionPageSize
+	}
 
-	return session, s.sessionRepo.UpdateSession(session)
+	page, err := s.sessionRepo.FindSessions(query)
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return nil, ErrInvalidCursor
+	}
+	return page, err
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   REANUDAR BREAK
+//   LÍNEA DE TIEMPO
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) ResumeBreak(id string) (*domain.Session, error) {
+// GetSessionEvents devuelve la línea de tiempo de una sesión en orden
+// cronológico.
+func (s *SessionService) GetSessionEvents(id string) ([]domain.SessionEvent, error) {
 	session, err := s.sessionRepo.FindByID(id)
 	if err != nil {
 		return nil, ErrSessionNotFound
 	}
 
-	if session.State != domain.SessionStateBreakPaused {
-		return nil, ErrInvalidStateTransition
+	if session.Events == nil {
+		return []domain.SessionEvent{}, nil
 	}
+	return session.Events, nil
+}
 
-	now := time.Now()
-	session.State = domain.SessionStateBreakRunning
-	session.PausedAt = nil
-	session.UpdatedAt = now
+//
+// ─────────────────────────────────────────────────────────────
+//   CADENCIA DE DESCANSOS
+// ─────────────────────────────────────────────────────────────
+//
+
+// GetBreakCadence devuelve la cadencia configurada por el usuario.
+func (s *SessionService) GetBreakCadence(userID string) (*domain.BreakCadence, error) {
+	cadence, err := s.prefsRepo.GetCadence(userID)
+	if err != nil {
+		return nil, err
+	}
+	if cadence == nil {
+		return nil, ErrCadenceNotFound
+	}
+	return cadence, nil
+}
 
-	return session, s.sessionRepo.UpdateSession(session)
+// SetBreakCadence crea o reemplaza la cadencia del usuario. Aplica a los
+// descansos que se inicien a partir de ahora.
+func (s *SessionService) SetBreakCadence(userID string, cadence *domain.BreakCadence) (*domain.BreakCadence, error) {
+	if err := s.prefsRepo.SaveCadence(userID, cadence); err != nil {
+		return nil, err
+	}
+	return cadence, nil
+}
+
+// nextSetPosition calcula la posición del nuevo pomodoro a partir de la
+// última sesión del usuario: si su focus no llegó a completarse, el nuevo
+// pomodoro ocupa el mismo lugar; si se completó, avanza y reinicia el set
+// tras el último pomodoro.
+func (s *SessionService) nextSetPosition(userID string, cadence *domain.BreakCadence) (int, error) {
+	prev, err := s.sessionRepo.FindLatestByUser(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if prev == nil || prev.SetPosition == 0 {
+		return 1, nil
+	}
+
+	if prev.FinishedAt == nil {
+		return prev.SetPosition, nil
+	}
+
+	if prev.SetPosition >= cadence.PomodorosPerSet {
+		return 1, nil
+	}
+	return prev.SetPosition + 1, nil
 }
 
 //
 // ─────────────────────────────────────────────────────────────
-//   FINALIZAR BREAK
+//   ANALÍTICA DE INTERRUPCIONES
 // ─────────────────────────────────────────────────────────────
 //
 
-func (s *SessionService) FinishBreak(id string) (*domain.Session, error) {
-	session, err := s.sessionRepo.FindByID(id)
+// InterruptionStats resume las interrupciones de un usuario o de una tarea.
+type InterruptionStats struct {
+	Total        int                        `json:"total"`
+	Internal     int                        `json:"internal"`
+	External     int                        `json:"external"`
+	Unclassified int                        `json:"unclassified"`
+	ByReason     []domain.InterruptionCount `json:"by_reason"`
+}
+
+// GetInterruptionStats agrega las interrupciones según el filtro indicado.
+func (s *SessionService) GetInterruptionStats(filter domain.InterruptionFilter) (*InterruptionStats, error) {
+	counts, err := s.sessionRepo.CountInterruptions(filter)
 	if err != nil {
-		return nil, ErrSessionNotFound
+		return nil, err
 	}
 
-	if session.State != domain.SessionStateBreakRunning &&
-		session.State != domain.SessionStateBreakPaused {
-		return nil, ErrInvalidStateTransition
+	stats := &InterruptionStats{ByReason: counts}
+	for _, c := range counts {
+		stats.Total += c.Count
+		switch c.Type {
+		case domain.InterruptionInternal:
+			stats.Internal += c.Count
+		case domain.InterruptionExternal:
+			stats.External += c.Count
+		default:
+			stats.Unclassified += c.Count
+		}
 	}
 
-	now := time.Now()
-	session.State = domain.SessionStateBreakFinished
-	session.BreakFinishedAt = &now
-	session.UpdatedAt = now
+	return stats, nil
+}
+
+//
+// ─────────────────────────────────────────────────────────────
+//   CANCELAR SESIÓN
+// ─────────────────────────────────────────────────────────────
+//
+
+// CancelSession abandona una sesión activa (focus o break). Una sesión
+// cancelada es terminal y no suma métricas a la tarea asociada.
+//
+// Como el resto de transiciones, expectedVersion (0 = sin precondición)
+// permite al cliente detectar que la sesión cambió desde que la leyó.
+func (s *SessionService) CancelSession(id string, expectedVersion int64, reason string) (*domain.Session, error) {
+	session, err := s.loadSession(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	from := session.State
+	event, err := s.machine.Apply(session, domain.SessionActionCancel, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	event.Reason = reason
 
-	return session, s.sessionRepo.UpdateSession(session)
+	return s.commit(session, domain.SessionActionCancel, from)
 }
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// ─────────────────────────────────────────────────────────────
+//   FAKES
+// ─────────────────────────────────────────────────────────────
+
+// fakeSessionRepo guarda las sesiones en memoria y reproduce la semántica
+// de MongoSessionRepository.TransitionSession: una comparación atómica de
+// estado y versión. Los métodos no implementados entran en pánico a través
+// de la interfaz embebida nula.
+type fakeSessionRepo struct {
+	domain.SessionRepository
+
+	mu       sync.Mutex
+	sessions map[string]domain.Session
+
+	// loads, si no es nil, retiene cada FindByID hasta que todos los
+	// lectores esperados tengan la misma instantánea.
+	loads *sync.WaitGroup
+}
+
+func newFakeSessionRepo(sessions ...*domain.Session) *fakeSessionRepo {
+	r := &fakeSessionRepo{sessions: make(map[string]domain.Session)}
+	for _, s := range sessions {
+		r.sessions[s.ID] = copySession(s)
+	}
+	return r
+}
+
+func (r *fakeSessionRepo) FindByID(id string) (*domain.Session, error) {
+	r.mu.Lock()
+	stored, ok := r.sessions[id]
+	r.mu.Unlock()
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	if r.loads != nil {
+		r.loads.Done()
+		r.loads.Wait()
+	}
+
+	s := copySession(&stored)
+	return &s, nil
+}
+
+func (r *fakeSessionRepo) TransitionSession(s *domain.Session, from domain.SessionState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[s.ID]
+	if !ok {
+		return errors.New("not found")
+	}
+	if stored.State != from {
+		return domain.ErrStateConflict
+	}
+	if stored.Version != s.Version {
+		return &domain.VersionConflictError{Entity: "session", ID: s.ID, Version: s.Version}
+	}
+
+	s.Version++
+	r.sessions[s.ID] = copySession(s)
+	return nil
+}
+
+func (r *fakeSessionRepo) CreateSession(s *domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s.State.Active() {
+		for _, other := range r.sessions {
+			if other.UserID == s.UserID && other.State.Active() {
+				return domain.ErrActiveSessionExists
+			}
+		}
+	}
+
+	s.ID = fmt.Sprintf("s%d", len(r.sessions)+1)
+	s.Version = 1
+	r.sessions[s.ID] = copySession(s)
+	return nil
+}
+
+func (r *fakeSessionRepo) FindActiveByUser(userID string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.sessions {
+		if stored.UserID == userID && stored.State.Active() {
+			s := copySession(&stored)
+			return &s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSessionRepo) stored(id string) domain.Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sessions[id]
+}
+
+func copySession(s *domain.Session) domain.Session {
+	c := *s
+	c.Events = append([]domain.SessionEvent(nil), s.Events...)
+	return c
+}
+
+type fakePreferencesRepo struct {
+	domain.PreferencesRepository
+}
+
+func (fakePreferencesRepo) GetPreferences(string) (*domain.UserPreferences, error) { return nil, nil }
+func (fakePreferencesRepo) GetCadence(string) (*domain.BreakCadence, error)        { return nil, nil }
+
+type fakeCycleRepo struct {
+	domain.CycleRepository
+
+	mu    sync.Mutex
+	saved []*domain.PomodoroCycle
+}
+
+func (r *fakeCycleRepo) Save(c *domain.PomodoroCycle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, c)
+	return nil
+}
+
+func (r *fakeCycleRepo) MarkBreakUsed(string) error { return nil }
+
+// failingTaskRepo rechaza toda actualización de métricas.
+type failingTaskRepo struct {
+	domain.TaskRepository
+}
+
+func (failingTaskRepo) AddRealMinutes(string, int) error    { return errors.New("mongo caído") }
+func (failingTaskRepo) IncrementPomodoroCount(string) error { return errors.New("mongo caído") }
+
+// ─────────────────────────────────────────────────────────────
+//   CONCURRENCIA
+// ─────────────────────────────────────────────────────────────
+
+func runningSession() *domain.Session {
+	started := time.Now().Add(-10 * time.Minute)
+	endsAt := started.Add(25 * time.Minute)
+	return &domain.Session{
+		ID:           "s1",
+		UserID:       "u1",
+		FocusMinutes: 25,
+		BreakMinutes: 5,
+		State:        domain.SessionStateRunning,
+		StartedAt:    started,
+		PhaseEndsAt:  &endsAt,
+		Version:      1,
+	}
+}
+
+// TestConcurrentPauseFinishSingleWinner lanza pausas y finalizaciones
+// simultáneas sobre la misma sesión: exactamente una debe persistirse y el
+// resto fallar con ErrInvalidStateTransition o ErrVersionConflict.
+func TestConcurrentPauseFinishSingleWinner(t *testing.T) {
+	const workers = 16
+
+	cases := []struct {
+		name string
+		// expectedVersion es el If-Match de los clientes (0 = sin él)
+		expectedVersion int64
+		// sameSnapshot obliga a que todos lean la sesión antes de escribir,
+		// de modo que la única defensa es la escritura condicional
+		sameSnapshot bool
+	}{
+		{name: "same snapshot without If-Match", expectedVersion: 0, sameSnapshot: true},
+		{name: "same snapshot with If-Match", expectedVersion: 1, sameSnapshot: true},
+		{name: "free-running with If-Match", expectedVersion: 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeSessionRepo(runningSession())
+			if tc.sameSnapshot {
+				repo.loads = &sync.WaitGroup{}
+				repo.loads.Add(workers)
+			}
+			cycles := &fakeCycleRepo{}
+			svc := NewSessionService(repo, nil, cycles, fakePreferencesRepo{}, nil)
+			defer svc.StopTimers()
+
+			var (
+				wg      sync.WaitGroup
+				start   = make(chan struct{})
+				results = make([]error, workers)
+				winners = make([]*domain.Session, workers)
+			)
+			for i := 0; i < workers; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					if i%2 == 0 {
+						winners[i], results[i] = svc.PauseSession("s1", tc.expectedVersion, domain.Interruption{})
+					} else {
+						winners[i], results[i] = svc.FinishSession("s1", tc.expectedVersion)
+					}
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+
+			won := -1
+			for i, err := range results {
+				switch {
+				case err == nil:
+					if won >= 0 {
+						t.Fatalf("dos ganadores: workers %d y %d", won, i)
+					}
+					won = i
+				case errors.Is(err, ErrInvalidStateTransition), errors.Is(err, ErrVersionConflict):
+				default:
+					t.Fatalf("worker %d: error inesperado %v", i, err)
+				}
+			}
+			if won < 0 {
+				t.Fatal("ninguna transición se persistió")
+			}
+
+			stored := repo.stored("s1")
+			if stored.Version != 2 {
+				t.Errorf("versión persistida = %d, want 2 (una sola escritura)", stored.Version)
+			}
+			if stored.State != winners[won].State {
+				t.Errorf("estado persistido = %s, want %s del ganador", stored.State, winners[won].State)
+			}
+			if n := len(stored.Events); n != 1 {
+				t.Errorf("eventos persistidos = %d, want 1", n)
+			}
+
+			wantCycles := 0
+			if won%2 == 1 {
+				wantCycles = 1 // Solo un FinishSession ganador registra ciclo
+			}
+			if len(cycles.saved) != wantCycles {
+				t.Errorf("ciclos registrados = %d, want %d", len(cycles.saved), wantCycles)
+			}
+		})
+	}
+}
+
+// TestTransitionRejectsStaleIfMatch comprueba que una precondición
+// obsoleta no llega a escribir.
+func TestTransitionRejectsStaleIfMatch(t *testing.T) {
+	repo := newFakeSessionRepo(runningSession())
+	svc := NewSessionService(repo, nil, &fakeCycleRepo{}, fakePreferencesRepo{}, nil)
+	defer svc.StopTimers()
+
+	for _, v := range []int64{2, 7} {
+		t.Run(fmt.Sprintf("version %d", v), func(t *testing.T) {
+			_, err := svc.PauseSession("s1", v, domain.Interruption{})
+			if !errors.Is(err, ErrVersionConflict) {
+				t.Fatalf("err = %v, want ErrVersionConflict", err)
+			}
+			if got := repo.stored("s1"); got.Version != 1 || got.State != domain.SessionStateRunning {
+				t.Fatalf("la sesión cambió: state %s, version %d", got.State, got.Version)
+			}
+		})
+	}
+}
+
+// TestFinishSessionLogsTaskMetricErrors comprueba que un fallo al acreditar
+// el focus a la tarea queda registrado con la sesión y la tarea, y no impide
+// guardar el ciclo.
+func TestFinishSessionLogsTaskMetricErrors(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	session := runningSession()
+	taskID := "t1"
+	session.TaskID = &taskID
+	cycles := &fakeCycleRepo{}
+	svc := NewSessionService(newFakeSessionRepo(session), failingTaskRepo{}, cycles, fakePreferencesRepo{}, nil)
+	defer svc.StopTimers()
+
+	if _, err := svc.FinishSession("s1", 1); err != nil {
+		t.Fatalf("FinishSession: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("líneas de log = %q, want 2", lines)
+	}
+	for _, line := range lines {
+		if !strings.Contains(line, "s1") || !strings.Contains(line, "t1") || !strings.Contains(line, "mongo caído") {
+			t.Errorf("log %q no identifica sesión, tarea y error", line)
+		}
+	}
+	if len(cycles.saved) != 1 {
+		t.Errorf("ciclos registrados = %d, want 1", len(cycles.saved))
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+//   SALTAR BREAK
+// ─────────────────────────────────────────────────────────────
+
+func finishedSession() *domain.Session {
+	s := runningSession()
+	finished := s.StartedAt.Add(25 * time.Minute)
+	s.State = domain.SessionStateFinished
+	s.FinishedAt = &finished
+	s.PhaseEndsAt = nil
+	return s
+}
+
+func TestSkipBreakStartsNextSession(t *testing.T) {
+	repo := newFakeSessionRepo(finishedSession())
+	svc := NewSessionService(repo, nil, &fakeCycleRepo{}, fakePreferencesRepo{}, nil)
+	defer svc.StopTimers()
+
+	skipped, next, err := svc.SkipBreak("s1", 1, true)
+	if err != nil {
+		t.Fatalf("SkipBreak: %v", err)
+	}
+	if skipped.State != domain.SessionStateBreakSkipped {
+		t.Errorf("state = %s, want %s", skipped.State, domain.SessionStateBreakSkipped)
+	}
+	if next == nil || next.State != domain.SessionStateRunning || next.FocusMinutes != skipped.FocusMinutes {
+		t.Fatalf("next = %+v, want un focus de %d minutos en curso", next, skipped.FocusMinutes)
+	}
+}
+
+// TestSkipBreakKeepsSessionWhenNextCannotStart comprueba que, si el
+// usuario ya tiene otra sesión activa, el descanso no se salta.
+func TestSkipBreakKeepsSessionWhenNextCannotStart(t *testing.T) {
+	other := runningSession()
+	other.ID = "s2"
+	repo := newFakeSessionRepo(finishedSession(), other)
+	svc := NewSessionService(repo, nil, &fakeCycleRepo{}, fakePreferencesRepo{}, nil)
+	defer svc.StopTimers()
+
+	skipped, next, err := svc.SkipBreak("s1", 1, true)
+	if !errors.Is(err, ErrActiveSessionExists) {
+		t.Fatalf("err = %v, want ErrActiveSessionExists", err)
+	}
+	if skipped != nil || next != nil {
+		t.Fatalf("skipped = %v, next = %v, want nil", skipped, next)
+	}
+	if got := repo.stored("s1"); got.State != domain.SessionStateFinished || got.Version != 1 {
+		t.Fatalf("la sesión cambió: state %s, version %d", got.State, got.Version)
+	}
+}
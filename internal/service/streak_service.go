@@ -0,0 +1,116 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// StreakService mantiene las rachas de días en que cada usuario alcanzó
+// su objetivo diario de foco (o MinimumStreakGoal si no tiene uno).
+type StreakService struct {
+	repo        domain.StreakRepository
+	sessionRepo domain.SessionRepository
+	prefsRepo   domain.PreferencesRepository
+}
+
+// NewStreakService crea el servicio.
+func NewStreakService(r domain.StreakRepository, sr domain.SessionRepository, pr domain.PreferencesRepository) *StreakService {
+	return &StreakService{repo: r, sessionRepo: sr, prefsRepo: pr}
+}
+
+// RegisterHooks suscribe el servicio al fin de cada focus, manual o por
+// vencimiento, para actualizar la racha de forma incremental.
+func (s *StreakService) RegisterHooks(sessions *SessionService) {
+	sessions.OnTransition(domain.SessionActionFinish, s.onFocusCompleted)
+	sessions.OnTransition(domain.SessionActionExpireFocus, s.onFocusCompleted)
+}
+
+// StreakView es la racha tal como la ve el cliente en un momento dado.
+type StreakView struct {
+	domain.UserStreak
+	QualifiedToday bool             `json:"qualified_today"`
+	Goal           domain.FocusGoal `json:"goal"`
+}
+
+// GetStreak devuelve la racha del usuario evaluada en su día local actual.
+func (s *StreakService) GetStreak(userID string) (*StreakView, error) {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	streak, err := s.repo.GetStreak(userID)
+	if err != nil {
+		return nil, err
+	}
+	if streak == nil {
+		streak = &domain.UserStreak{UserID: userID}
+	}
+
+	today, _ := prefs.DayRange(time.Now())
+
+	view := &StreakView{
+		UserStreak:     *streak,
+		QualifiedToday: streak.LastQualifiedDay == today.Format(domain.StreakDayLayout),
+		Goal:           streakGoal(prefs),
+	}
+	view.Current = streak.CurrentAt(today)
+
+	return view, nil
+}
+
+// onFocusCompleted comprueba si el día local del focus terminado alcanza
+// el objetivo y, si es así, lo suma a la racha. Solo consulta los totales
+// de ese día, nunca el historial completo.
+func (s *StreakService) onFocusCompleted(session *domain.Session, _ domain.SessionState) {
+	if session.FinishedAt == nil {
+		return
+	}
+
+	if err := s.recordDay(session.UserID, *session.FinishedAt); err != nil {
+		log.Printf("no se pudo actualizar la racha de %s: %v", session.UserID, err)
+	}
+}
+
+func (s *StreakService) recordDay(userID string, at time.Time) error {
+	prefs, err := loadPreferences(s.prefsRepo, userID)
+	if err != nil {
+		return err
+	}
+
+	streak, err := s.repo.GetStreak(userID)
+	if err != nil {
+		return err
+	}
+	if streak == nil {
+		streak = &domain.UserStreak{UserID: userID}
+	}
+
+	day, next := prefs.DayRange(at)
+	if streak.LastQualifiedDay == day.Format(domain.StreakDayLayout) {
+		return nil
+	}
+
+	totals, err := s.sessionRepo.SumFocus(userID, day, next)
+	if err != nil {
+		return err
+	}
+	if !streakGoal(prefs).Met(*totals) {
+		return nil
+	}
+
+	streak.Qualify(day)
+	streak.UpdatedAt = time.Now()
+
+	return s.repo.SaveStreak(streak)
+}
+
+// streakGoal es el objetivo diario que cuenta para la racha.
+func streakGoal(prefs *domain.UserPreferences) domain.FocusGoal {
+	if prefs.DailyGoal != nil {
+		return *prefs.DailyGoal
+	}
+	return domain.MinimumStreakGoal
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"time"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// TagService gestiona las etiquetas de cada usuario.
+type TagService struct {
+	repo     domain.TagRepository
+	taskRepo domain.TaskRepository
+}
+
+// NewTagService crea el servicio.
+func NewTagService(r domain.TagRepository, tr domain.TaskRepository) *TagService {
+	return &TagService{repo: r, taskRepo: tr}
+}
+
+//
+// ──────────────────────────────────────────────
+//   CREAR ETIQUETA
+// ──────────────────────────────────────────────
+//
+
+func (s *TagService) CreateTag(userID, name, color string) (*domain.Tag, error) {
+	if color == "" {
+		color = domain.DefaultTagColor
+	}
+
+	now := time.Now()
+	tag := &domain.Tag{
+		UserID:    userID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.repo.Create(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   CONSULTAR ETIQUETAS
+// ──────────────────────────────────────────────
+//
+
+func (s *TagService) GetTag(id string) (*domain.Tag, error) {
+	tag, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+func (s *TagService) GetTagsByUser(userID string) ([]*domain.Tag, error) {
+	return s.repo.FindByUser(userID)
+}
+
+//
+// ──────────────────────────────────────────────
+//   ACTUALIZAR ETIQUETA
+// ──────────────────────────────────────────────
+//
+
+func (s *TagService) UpdateTag(id, name, color string) (*domain.Tag, error) {
+	tag, err := s.GetTag(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if color == "" {
+		color = domain.DefaultTagColor
+	}
+
+	tag.Name = name
+	tag.Color = color
+	tag.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   ELIMINAR ETIQUETA
+// ──────────────────────────────────────────────
+//
+
+// DeleteTag elimina la etiqueta y la desasigna de todas sus tareas.
+func (s *TagService) DeleteTag(id string) error {
+	if err := s.repo.Delete(id); err != nil {
+		return ErrTagNotFound
+	}
+	return s.taskRepo.RemoveTag(id)
+}
@@ -7,11 +7,12 @@ import (
 )
 
 type TaskService struct {
-	repo domain.TaskRepository
+	repo    domain.TaskRepository
+	tagRepo domain.TagRepository
 }
 
-func NewTaskService(r domain.TaskRepository) *TaskService {
-	return &TaskService{repo: r}
+func NewTaskService(r domain.TaskRepository, tags domain.TagRepository) *TaskService {
+	return &TaskService{repo: r, tagRepo: tags}
 }
 
 //
@@ -20,7 +21,12 @@ func NewTaskService(r domain.TaskRepository) *TaskService {
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) CreateTask(userID, title, desc string, projectID *string) (*domain.Task, error) {
+func (s *TaskService) CreateTask(userID, title, desc string, projectID, parentID *string, estimated *int, autoComplete bool) (*domain.Task, error) {
+
+	parent, err := s.loadParent(userID, parentID)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 
@@ -30,13 +36,22 @@ func (s *TaskService) CreateTask(userID, title, desc string, projectID *string)
 		Title:              title,
 		Description:        desc,
 		ProjectID:          projectID,
+		EstimatedPomodoros: estimated,
+		AutoComplete:       autoComplete,
+		TagIDs:             []string{},
+		Checklist:          []domain.ChecklistItem{},
 		Status:             domain.TaskStatusPending,
 		Completed:          false,
 		PomodorosCompleted: 0,
 		TotalFocusMinutes:  0,
+		OwnPomodoros:       0,
+		OwnFocusMinutes:    0,
 		CreatedAt:          now,
 		UpdatedAt:          now,
 	}
+	if err := task.SetParent(parent); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Create(task); err != nil {
 		return nil, err
@@ -61,7 +76,12 @@ func (s *TaskService) GetTask(id string) (*domain.Task, error) {
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) GetTasksByUser(userID string) ([]*domain.Task, error) {
+// GetTasksByUser devuelve las tareas del usuario; con tagIDs, solo las que
+// tienen todas esas etiquetas.
+func (s *TaskService) GetTasksByUser(userID string, tagIDs []string) ([]*domain.Task, error) {
+	if len(tagIDs) > 0 {
+		return s.repo.FindByTags(userID, tagIDs)
+	}
 	return s.repo.FindByUser(userID)
 }
 
@@ -71,16 +91,18 @@ func (s *TaskService) GetTasksByUser(userID string) ([]*domain.Task, error) {
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) UpdateTask(id, title, desc string, projectID *string) (*domain.Task, error) {
+func (s *TaskService) UpdateTask(id string, expectedVersion int64, title, desc string, projectID *string, estimated *int, autoComplete bool) (*domain.Task, error) {
 
-	task, err := s.repo.FindByID(id)
+	task, err := s.loadTask(id, expectedVersion)
 	if err != nil {
-		return nil, ErrTaskNotFound
+		return nil, err
 	}
 
 	task.Title = title
 	task.Description = desc
 	task.ProjectID = projectID
+	task.EstimatedPomodoros = estimated
+	task.AutoComplete = autoComplete
 	task.UpdatedAt = time.Now()
 
 	if err := s.repo.Update(task); err != nil {
@@ -96,20 +118,165 @@ func (s *TaskService) UpdateTask(id, title, desc string, projectID *string) (*do
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) DeleteTask(id string) error {
-	return s.repo.Delete(id)
+// DeleteTask elimina la tarea y descuenta sus métricas de los ancestros.
+// Si tiene subtareas devuelve ErrTaskHasChildren, salvo que cascade pida
+// eliminar el subárbol completo.
+func (s *TaskService) DeleteTask(id string, cascade bool) error {
+	task, err := s.repo.FindByID(id)
+	if err != nil {
+		return ErrTaskNotFound
+	}
+
+	children, err := s.repo.FindChildren(id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(children) == 0:
+		err = s.repo.Delete(id)
+	case cascade:
+		err = s.repo.DeleteSubtree(id)
+	default:
+		return ErrTaskHasChildren
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.AddMetrics(task.AncestorIDs, -task.PomodorosCompleted, -task.TotalFocusMinutes)
 }
 
 //
 // ──────────────────────────────────────────────
-//   MARCAR COMPLETADA
+//   JERARQUÍA
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) MarkCompleted(id string) error {
+// GetChildren devuelve las subtareas directas de la tarea.
+func (s *TaskService) GetChildren(id string) ([]*domain.Task, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, ErrTaskNotFound
+	}
+	return s.repo.FindChildren(id)
+}
+
+// MoveTask cuelga la tarea de parentID, o la lleva a la raíz si es nil.
+// Sus descendientes la acompañan y sus métricas acumuladas pasan de los
+// ancestros anteriores a los nuevos.
+//
+// El movimiento no es atómico: son tres escrituras (la tarea, la ruta de
+// sus descendientes y las métricas de ambas ramas). La comprobación de
+// ciclos se repite después de escribir la ruta, porque dos movimientos
+// concurrentes (A bajo B y B bajo A) pasan cada uno la validación previa;
+// si el padre quedó entre los descendientes, el movimiento se deshace
+// antes de trasladar métricas y se devuelve ErrTaskCycle.
+//
+// Los incrementos de métricas del subárbol cambian la versión de la tarea,
+// así que uno ocurrido desde la lectura hace fallar Update con un conflicto.
+// Si llega uno entre la escritura de la ruta y el traslado de métricas, pudo
+// sumarse a la rama anterior o a la nueva: en ese caso, en lugar de
+// trasladar la instantánea, se recalculan los acumulados de ambas ramas.
+func (s *TaskService) MoveTask(id string, expectedVersion int64, parentID *string) (*domain.Task, error) {
+	task, err := s.loadTask(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.loadParent(task.UserID, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldParentID, oldAncestors := task.ParentID, task.AncestorIDs
+	if err := task.SetParent(parent); err != nil {
+		return nil, err
+	}
+	task.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(task); err != nil {
+		return nil, err
+	}
+	if err := s.repo.MoveDescendants(task.ID, task.Path()); err != nil {
+		return nil, err
+	}
+
+	if parent != nil {
+		current, err := s.repo.FindByID(parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		if task.IsAncestorOf(current) {
+			if err := s.undoMove(task.ID, oldParentID, oldAncestors); err != nil {
+				return nil, err
+			}
+			return nil, ErrTaskCycle
+		}
+	}
+
+	moved, err := s.repo.FindByID(task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if moved.Version != task.Version {
+		branches := append(append([]string{}, oldAncestors...), task.AncestorIDs...)
+		if err := s.repo.RecomputeMetrics(branches); err != nil {
+			return nil, err
+		}
+		return s.repo.FindByID(task.ID)
+	}
+
+	if err := s.repo.MoveMetrics(oldAncestors, task.AncestorIDs, task.PomodorosCompleted, task.TotalFocusMinutes); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// undoMove devuelve la tarea y su subárbol a la ruta anterior a un
+// movimiento que formó un ciclo. Parte de la tarea recién leída porque el
+// movimiento concurrente pudo reescribir su ruta y su versión.
+func (s *TaskService) undoMove(id string, parentID *string, ancestors []string) error {
 	task, err := s.repo.FindByID(id)
 	if err != nil {
-		return ErrTaskNotFound
+		return err
+	}
+
+	task.ParentID = parentID
+	task.AncestorIDs = ancestors
+	task.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(task); err != nil {
+		return err
+	}
+	return s.repo.MoveDescendants(task.ID, task.Path())
+}
+
+// loadParent recupera el padre indicado, que debe existir y pertenecer al
+// mismo usuario. parentID nil significa tarea raíz.
+func (s *TaskService) loadParent(userID string, parentID *string) (*domain.Task, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+
+	parent, err := s.repo.FindByID(*parentID)
+	if err != nil || parent.UserID != userID {
+		return nil, ErrParentTaskNotFound
+	}
+
+	return parent, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   MARCAR COMPLETADA
+// ──────────────────────────────────────────────
+//
+
+func (s *TaskService) MarkCompleted(id string, expectedVersion int64) error {
+	task, err := s.loadTask(id, expectedVersion)
+	if err != nil {
+		return err
 	}
 
 	now := time.Now()
@@ -127,10 +294,10 @@ func (s *TaskService) MarkCompleted(id string) error {
 // ──────────────────────────────────────────────
 //
 
-func (s *TaskService) UpdateStatus(id string, status domain.TaskStatus) error {
-	task, err := s.repo.FindByID(id)
+func (s *TaskService) UpdateStatus(id string, expectedVersion int64, status domain.TaskStatus) error {
+	task, err := s.loadTask(id, expectedVersion)
 	if err != nil {
-		return ErrTaskNotFound
+		return err
 	}
 
 	task.Status = status
@@ -139,6 +306,142 @@ func (s *TaskService) UpdateStatus(id string, status domain.TaskStatus) error {
 	return s.repo.Update(task)
 }
 
+//
+// ──────────────────────────────────────────────
+//   CHECKLIST
+// ──────────────────────────────────────────────
+//
+
+// AddChecklistItem agrega un paso al final de la lista de la tarea.
+func (s *TaskService) AddChecklistItem(id string, expectedVersion int64, title string) (*domain.Task, error) {
+	return s.updateChecklist(id, expectedVersion, func(task *domain.Task, now time.Time) error {
+		return task.AddChecklistItem(domain.ChecklistItem{
+			ID:        GenerateID(),
+			Title:     title,
+			CreatedAt: now,
+		})
+	})
+}
+
+// ToggleChecklistItem marca o desmarca un paso. Si la tarea tiene
+// AutoComplete y todos los pasos quedan hechos, se completa en la misma
+// escritura.
+func (s *TaskService) ToggleChecklistItem(id string, expectedVersion int64, itemID string) (*domain.Task, error) {
+	return s.updateChecklist(id, expectedVersion, func(task *domain.Task, now time.Time) error {
+		if err := task.ToggleChecklistItem(itemID, now); err != nil {
+			return err
+		}
+		task.CompleteIfChecklistDone(now)
+		return nil
+	})
+}
+
+// RemoveChecklistItem elimina un paso de la tarea. Si los pasos restantes
+// ya estaban todos hechos, AutoComplete completa la tarea igual que al
+// marcar el último.
+func (s *TaskService) RemoveChecklistItem(id string, expectedVersion int64, itemID string) (*domain.Task, error) {
+	return s.updateChecklist(id, expectedVersion, func(task *domain.Task, now time.Time) error {
+		if err := task.RemoveChecklistItem(itemID); err != nil {
+			return err
+		}
+		task.CompleteIfChecklistDone(now)
+		return nil
+	})
+}
+
+// ReorderChecklist reordena los pasos; itemIDs debe incluirlos todos.
+func (s *TaskService) ReorderChecklist(id string, expectedVersion int64, itemIDs []string) (*domain.Task, error) {
+	return s.updateChecklist(id, expectedVersion, func(task *domain.Task, _ time.Time) error {
+		return task.ReorderChecklist(itemIDs)
+	})
+}
+
+// updateChecklist aplica mutate sobre la tarea y la persiste con la misma
+// precondición de versión que el resto de actualizaciones.
+func (s *TaskService) updateChecklist(id string, expectedVersion int64, mutate func(*domain.Task, time.Time) error) (*domain.Task, error) {
+	task, err := s.loadTask(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := mutate(task, now); err != nil {
+		return nil, err
+	}
+	task.UpdatedAt = now
+
+	if err := s.repo.Update(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+//
+// ──────────────────────────────────────────────
+//   ETIQUETAS
+// ──────────────────────────────────────────────
+//
+
+// SetTags reemplaza las etiquetas de la tarea. Todas deben existir y ser
+// del mismo usuario; los IDs repetidos se ignoran.
+func (s *TaskService) SetTags(id string, expectedVersion int64, tagIDs []string) (*domain.Task, error) {
+	task, err := s.loadTask(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	unique := make([]string, 0, len(tagIDs))
+	seen := make(map[string]bool, len(tagIDs))
+	for _, tagID := range tagIDs {
+		if !seen[tagID] {
+			seen[tagID] = true
+			unique = append(unique, tagID)
+		}
+	}
+
+	if len(unique) > domain.MaxTaskTags {
+		return nil, ErrTooManyTags
+	}
+	if len(unique) > 0 {
+		tags, err := s.tagRepo.FindByIDs(task.UserID, unique)
+		if err != nil {
+			return nil, err
+		}
+		if len(tags) != len(unique) {
+			return nil, ErrTagNotFound
+		}
+	}
+
+	task.TagIDs = unique
+	task.UpdatedAt = time.Now()
+
+	if err := s.repo.Update(task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// loadTask recupera la tarea y valida la precondición de versión del
+// cliente (If-Match). expectedVersion 0 significa sin precondición.
+func (s *TaskService) loadTask(id string, expectedVersion int64) (*domain.Task, error) {
+	task, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrTaskNotFound
+	}
+
+	if expectedVersion != 0 && task.Version != expectedVersion {
+		return nil, &domain.VersionConflictError{
+			Entity:  "task",
+			ID:      id,
+			Version: expectedVersion,
+		}
+	}
+
+	return task, nil
+}
+
 //
 // ──────────────────────────────────────────────
 //   MÉTRICAS POMODORO DESDE SESSION SERVICE
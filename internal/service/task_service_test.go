@@ -0,0 +1,387 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"pomodoro-backend/internal/domain"
+)
+
+// fakeTaskRepo guarda las tareas en memoria con la misma semántica de
+// versión y de jerarquía que MongoTaskRepository. beforeUpdate y
+// beforeMove, si tienen una entrada para la tarea, la ejecutan una vez
+// antes de la siguiente escritura de la tarea o de su subárbol, para
+// intercalar una petición concurrente.
+type fakeTaskRepo struct {
+	domain.TaskRepository
+
+	mu    sync.Mutex
+	tasks map[string]domain.Task
+
+	beforeUpdate map[string]func()
+	beforeMove   map[string]func()
+}
+
+func newFakeTaskRepo(tasks ...*domain.Task) *fakeTaskRepo {
+	r := &fakeTaskRepo{
+		tasks:        make(map[string]domain.Task),
+		beforeUpdate: make(map[string]func()),
+		beforeMove:   make(map[string]func()),
+	}
+	for _, t := range tasks {
+		r.tasks[t.ID] = copyTask(t)
+	}
+	return r
+}
+
+func copyTask(t *domain.Task) domain.Task {
+	c := *t
+	c.AncestorIDs = append([]string(nil), t.AncestorIDs...)
+	c.Checklist = append([]domain.ChecklistItem(nil), t.Checklist...)
+	return c
+}
+
+func (r *fakeTaskRepo) FindByID(id string) (*domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.tasks[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	t := copyTask(&stored)
+	return &t, nil
+}
+
+func (r *fakeTaskRepo) Update(t *domain.Task) error {
+	if hook := r.beforeUpdate[t.ID]; hook != nil {
+		delete(r.beforeUpdate, t.ID)
+		hook()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tasks[t.ID].Version != t.Version {
+		return &domain.VersionConflictError{Entity: "task", ID: t.ID, Version: t.Version}
+	}
+	t.Version++
+	r.tasks[t.ID] = copyTask(t)
+	return nil
+}
+
+func (r *fakeTaskRepo) MoveDescendants(id string, path []string) error {
+	if hook := r.beforeMove[id]; hook != nil {
+		delete(r.beforeMove, id)
+		hook()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, t := range r.tasks {
+		if key == id {
+			continue
+		}
+		for i, ancestor := range t.AncestorIDs {
+			if ancestor == id {
+				t.AncestorIDs = append(append([]string{}, path...), t.AncestorIDs[i+1:]...)
+				t.Version++
+				r.tasks[key] = t
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (r *fakeTaskRepo) MoveMetrics(from, to []string, pomodoros, minutes int) error {
+	if pomodoros == 0 && minutes == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range from {
+		t := r.tasks[id]
+		t.PomodorosCompleted -= pomodoros
+		t.TotalFocusMinutes -= minutes
+		t.Version++
+		r.tasks[id] = t
+	}
+	for _, id := range to {
+		t := r.tasks[id]
+		t.PomodorosCompleted += pomodoros
+		t.TotalFocusMinutes += minutes
+		t.Version++
+		r.tasks[id] = t
+	}
+	return nil
+}
+
+// IncrementPomodoroCount suma un pomodoro de 25 minutos a la tarea y a
+// los ancestros de su ruta actual, incrementando sus versiones.
+func (r *fakeTaskRepo) IncrementPomodoroCount(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	self := r.tasks[id]
+	self.OwnPomodoros++
+	self.OwnFocusMinutes += 25
+	r.tasks[id] = self
+
+	for _, key := range append(append([]string{}, self.AncestorIDs...), id) {
+		t := r.tasks[key]
+		t.PomodorosCompleted++
+		t.TotalFocusMinutes += 25
+		t.Version++
+		r.tasks[key] = t
+	}
+	return nil
+}
+
+func (r *fakeTaskRepo) RecomputeMetrics(ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		pomodoros, minutes := 0, 0
+		for key, t := range r.tasks {
+			if key == id || containsID(t.AncestorIDs, id) {
+				pomodoros += t.OwnPomodoros
+				minutes += t.OwnFocusMinutes
+			}
+		}
+		t := r.tasks[id]
+		t.PomodorosCompleted, t.TotalFocusMinutes = pomodoros, minutes
+		t.Version++
+		r.tasks[id] = t
+	}
+	return nil
+}
+
+func containsID(ids []string, id string) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeTaskRepo) stored(id string) domain.Task {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tasks[id]
+}
+
+func rootTask(id string, pomodoros int) *domain.Task {
+	return &domain.Task{
+		ID:                 id,
+		UserID:             "u1",
+		Title:              id,
+		PomodorosCompleted: pomodoros,
+		TotalFocusMinutes:  pomodoros * 25,
+		OwnPomodoros:       pomodoros,
+		OwnFocusMinutes:    pomodoros * 25,
+		Version:            1,
+	}
+}
+
+func TestMoveTaskMovesMetricsBetweenBranches(t *testing.T) {
+	a, b, c := rootTask("a", 0), rootTask("b", 0), rootTask("c", 2)
+	b.ParentID, b.AncestorIDs = strPtr("a"), []string{"a"}
+	c.ParentID, c.AncestorIDs = strPtr("b"), []string{"a", "b"}
+	a.PomodorosCompleted, b.PomodorosCompleted = 2, 2
+	d := rootTask("d", 0)
+
+	repo := newFakeTaskRepo(a, b, c, d)
+	svc := NewTaskService(repo, nil)
+
+	if _, err := svc.MoveTask("c", 1, strPtr("d")); err != nil {
+		t.Fatalf("MoveTask: %v", err)
+	}
+
+	for id, want := range map[string]int{"a": 0, "b": 0, "c": 2, "d": 2} {
+		if got := repo.stored(id).PomodorosCompleted; got != want {
+			t.Errorf("pomodoros de %s = %d, want %d", id, got, want)
+		}
+	}
+	if got := repo.stored("c").AncestorIDs; !reflect.DeepEqual(got, []string{"d"}) {
+		t.Errorf("ruta de c = %v, want [d]", got)
+	}
+}
+
+// TestMoveTaskUndoesConcurrentCycle intercala «b bajo a» entre la
+// validación y la escritura de «a bajo b»: ambos pasan la comprobación
+// previa, pero el segundo en escribir no debe dejar un ciclo. Si b tenía
+// métricas, trasladarlas cambia la versión de a y su escritura falla con
+// un conflicto; si no, la escritura llega a hacerse y se deshace.
+func TestMoveTaskUndoesConcurrentCycle(t *testing.T) {
+	cases := []struct {
+		name       string
+		bPomodoros int
+		wantErr    func(error) bool
+	}{
+		{
+			name:       "cycle written and undone",
+			bPomodoros: 0,
+			wantErr:    func(err error) bool { return errors.Is(err, ErrTaskCycle) },
+		},
+		{
+			name:       "metrics move bumps the parent version",
+			bPomodoros: 1,
+			wantErr:    func(err error) bool { return errors.Is(err, ErrVersionConflict) },
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := newFakeTaskRepo(rootTask("a", 1), rootTask("b", tc.bPomodoros))
+			svc := NewTaskService(repo, nil)
+
+			var concurrentErr error
+			repo.beforeUpdate["a"] = func() {
+				_, concurrentErr = svc.MoveTask("b", 0, strPtr("a"))
+			}
+
+			_, err := svc.MoveTask("a", 0, strPtr("b"))
+			if concurrentErr != nil {
+				t.Fatalf("movimiento concurrente: %v", concurrentErr)
+			}
+			if !tc.wantErr(err) {
+				t.Fatalf("err = %v", err)
+			}
+
+			a, b := repo.stored("a"), repo.stored("b")
+			if a.ParentID != nil || len(a.AncestorIDs) != 0 {
+				t.Errorf("a quedó bajo %v (ruta %v), want raíz", a.ParentID, a.AncestorIDs)
+			}
+			if b.ParentID == nil || *b.ParentID != "a" || !reflect.DeepEqual(b.AncestorIDs, []string{"a"}) {
+				t.Errorf("b: padre %v, ruta %v, want bajo a", b.ParentID, b.AncestorIDs)
+			}
+			if want := 1 + tc.bPomodoros; a.PomodorosCompleted != want {
+				t.Errorf("pomodoros de a = %d, want %d", a.PomodorosCompleted, want)
+			}
+		})
+	}
+}
+
+// TestMoveTaskWithConcurrentIncrement completa un pomodoro de una subtarea
+// mientras su padre se mueve de a a d. Los acumulados deben quedar
+// completos en la rama nueva y a cero en la anterior.
+func TestMoveTaskWithConcurrentIncrement(t *testing.T) {
+	cases := []struct {
+		name string
+		// hook registra el incremento concurrente en el punto del movimiento
+		hook    func(repo *fakeTaskRepo, increment func())
+		wantErr error
+	}{
+		{
+			name: "between load and update",
+			hook: func(repo *fakeTaskRepo, increment func()) {
+				repo.beforeUpdate["b"] = increment
+			},
+			wantErr: ErrVersionConflict,
+		},
+		{
+			name: "between update and the descendants' path",
+			hook: func(repo *fakeTaskRepo, increment func()) {
+				repo.beforeMove["b"] = increment
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// a → b → c, con un pomodoro ya hecho en c
+			a, b, c, d := rootTask("a", 1), rootTask("b", 0), rootTask("c", 1), rootTask("d", 0)
+			a.OwnPomodoros, a.OwnFocusMinutes = 0, 0
+			b.PomodorosCompleted, b.TotalFocusMinutes = 1, 25
+			b.ParentID, b.AncestorIDs = strPtr("a"), []string{"a"}
+			c.ParentID, c.AncestorIDs = strPtr("b"), []string{"a", "b"}
+
+			repo := newFakeTaskRepo(a, b, c, d)
+			svc := NewTaskService(repo, nil)
+
+			tc.hook(repo, func() {
+				if err := repo.IncrementPomodoroCount("c"); err != nil {
+					t.Fatalf("IncrementPomodoroCount: %v", err)
+				}
+			})
+
+			_, err := svc.MoveTask("b", 1, strPtr("d"))
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tc.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("MoveTask: %v", err)
+			}
+
+			// El pomodoro concurrente está en la rama de b, se moviera o no
+			want := map[string]int{"a": 0, "b": 2, "c": 2, "d": 2}
+			if tc.wantErr != nil {
+				want = map[string]int{"a": 2, "b": 2, "c": 2, "d": 0}
+			}
+			for id, pomodoros := range want {
+				got := repo.stored(id)
+				if got.PomodorosCompleted != pomodoros || got.TotalFocusMinutes != pomodoros*25 {
+					t.Errorf("%s: %d pomodoros / %d min, want %d / %d",
+						id, got.PomodorosCompleted, got.TotalFocusMinutes, pomodoros, pomodoros*25)
+				}
+			}
+		})
+	}
+}
+
+// TestChecklistAutoCompleteSingleWrite comprueba que completar la tarea
+// por su lista ocurre en la misma escritura que el cambio de la lista.
+func TestChecklistAutoCompleteSingleWrite(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(svc *TaskService) (*domain.Task, error)
+	}{
+		{
+			name: "toggle the last pending item",
+			change: func(svc *TaskService) (*domain.Task, error) {
+				return svc.ToggleChecklistItem("a", 1, "pending")
+			},
+		},
+		{
+			name: "remove the last pending item",
+			change: func(svc *TaskService) (*domain.Task, error) {
+				return svc.RemoveChecklistItem("a", 1, "pending")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := rootTask("a", 0)
+			task.AutoComplete = true
+			task.Checklist = []domain.ChecklistItem{
+				{ID: "done", Title: "done", Done: true},
+				{ID: "pending", Title: "pending"},
+			}
+			repo := newFakeTaskRepo(task)
+			svc := NewTaskService(repo, nil)
+
+			got, err := tc.change(svc)
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if !got.Completed || got.Status != domain.TaskStatusCompleted || got.CompletedAt == nil {
+				t.Fatalf("tarea no completada: completed %v, status %s", got.Completed, got.Status)
+			}
+			if stored := repo.stored("a"); stored.Version != 2 || !stored.Completed {
+				t.Fatalf("persistida: version %d, completed %v; want una sola escritura completada", stored.Version, stored.Completed)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string { return &s }
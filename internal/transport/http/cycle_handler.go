@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CycleHandler expone el historial de ciclos Pomodoro completados.
+type CycleHandler struct {
+	svc *service.CycleService
+}
+
+// NewCycleHandler construye una nueva instancia de CycleHandler.
+func NewCycleHandler(svc *service.CycleService) *CycleHandler {
+	return &CycleHandler{svc: svc}
+}
+
+// RegisterRoutes registra los endpoints de consulta de ciclos.
+func (h *CycleHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	cycles := rg.Group("/cycles")
+	{
+		cycles.GET("/task/:taskID", h.getCyclesByTask)
+		cycles.GET("/user/:userID", h.getCyclesByUser)
+	}
+}
+
+// getCyclesByTask devuelve los ciclos registrados para una tarea.
+func (h *CycleHandler) getCyclesByTask(c *gin.Context) {
+	cycles, err := h.svc.GetCyclesByTask(c.Param("taskID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo ciclos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cycles)
+}
+
+// getCyclesByUser devuelve los ciclos de un usuario. Acepta los parámetros
+// opcionales ?from= y ?to= en formato RFC3339 para acotar por fecha.
+func (h *CycleHandler) getCyclesByUser(c *gin.Context) {
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro from inválido", "detail": err.Error()})
+		return
+	}
+
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro to inválido", "detail": err.Error()})
+		return
+	}
+
+	cycles, err := h.svc.GetCyclesByUser(c.Param("userID"), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo ciclos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cycles)
+}
+
+// parseTimeQuery lee un parámetro de query en formato RFC3339. Devuelve
+// nil si el parámetro no está presente.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
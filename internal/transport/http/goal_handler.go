@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GoalHandler expone los objetivos de foco y su progreso.
+type GoalHandler struct {
+	svc *service.GoalService
+}
+
+// NewGoalHandler construye una instancia del controlador HTTP.
+func NewGoalHandler(svc *service.GoalService) *GoalHandler {
+	return &GoalHandler{svc: svc}
+}
+
+// RegisterRoutes registra los endpoints de objetivos.
+func (h *GoalHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/users/:id/goals", h.getGoals)
+	rg.PUT("/users/:id/goals", h.putGoals)
+	rg.GET("/users/:id/goals/progress", h.getProgress)
+}
+
+// goalsRequest reemplaza los objetivos diario y semanal; uno omitido queda
+// sin configurar.
+type goalsRequest struct {
+	Daily  *focusGoalRequest `json:"daily"`
+	Weekly *focusGoalRequest `json:"weekly"`
+}
+
+// getGoals devuelve los objetivos configurados por el usuario.
+func (h *GoalHandler) getGoals(c *gin.Context) {
+	daily, weekly, err := h.svc.GetGoals(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo objetivos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"daily": daily, "weekly": weekly})
+}
+
+// putGoals reemplaza los objetivos del usuario.
+func (h *GoalHandler) putGoals(c *gin.Context) {
+	var req goalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	daily, weekly := req.Daily.toDomain(), req.Weekly.toDomain()
+	if err := h.svc.SetGoals(c.Param("id"), daily, weekly); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudieron guardar los objetivos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"daily": daily, "weekly": weekly})
+}
+
+// getProgress calcula el avance de los objetivos en el día y la semana
+// locales del usuario. ?at (RFC3339) permite consultar otro momento.
+func (h *GoalHandler) getProgress(c *gin.Context) {
+	at, err := parseTimeQuery(c, "at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro at inválido", "detail": err.Error()})
+		return
+	}
+	if at == nil {
+		now := time.Now()
+		at = &now
+	}
+
+	progress, err := h.svc.GetProgress(c.Param("id"), *at)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error calculando el progreso"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
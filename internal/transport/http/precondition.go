@@ -0,0 +1,60 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// parseIfMatch interpreta la cabecera If-Match como la versión que el
+// cliente espera modificar. Devuelve 0 (sin precondición) si la cabecera
+// no está presente o vale "*".
+func parseIfMatch(c *gin.Context) (int64, error) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" || raw == "*" {
+		return 0, nil
+	}
+
+	raw = strings.TrimPrefix(raw, "W/")
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+
+	version, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+
+	return version, nil
+}
+
+// setETag publica la versión de la entidad como ETag fuerte.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// writeIfMatchError responde 400 ante una cabecera If-Match mal formada.
+func writeIfMatchError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, gin.H{"error": "cabecera If-Match inválida", "detail": err.Error()})
+}
+
+// versionConflictStatus decide el código para un conflicto de versión:
+// 412 si el cliente envió una versión explícita que no se cumple y 409 si
+// perdió una carrera contra otra escritura concurrente. "If-Match: *" no
+// fija ninguna versión, así que cuenta como sin precondición.
+func versionConflictStatus(c *gin.Context, err error) (int, bool) {
+	if !errors.Is(err, service.ErrVersionConflict) {
+		return 0, false
+	}
+	if version, err := parseIfMatch(c); err == nil && version > 0 {
+		return http.StatusPreconditionFailed, true
+	}
+	return http.StatusConflict, true
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pomodoro-backend/internal/domain"
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// racingTaskRepo devuelve siempre la tarea en la versión 1 y rechaza toda
+// escritura como si otra petición hubiera ganado la carrera.
+type racingTaskRepo struct {
+	domain.TaskRepository
+}
+
+func (racingTaskRepo) FindByID(id string) (*domain.Task, error) {
+	return &domain.Task{ID: id, UserID: "u1", Title: "t", Version: 1}, nil
+}
+
+func (racingTaskRepo) Update(t *domain.Task) error {
+	return &domain.VersionConflictError{Entity: "task", ID: t.ID, Version: t.Version}
+}
+
+func TestVersionConflictStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	NewTaskHandler(service.NewTaskService(racingTaskRepo{}, nil)).RegisterRoutes(router.Group("/api/v1"))
+
+	cases := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{name: "no precondition", ifMatch: "", want: http.StatusConflict},
+		{name: "wildcard is no precondition", ifMatch: "*", want: http.StatusConflict},
+		{name: "matching version loses the race", ifMatch: `"1"`, want: http.StatusPreconditionFailed},
+		{name: "stale version", ifMatch: `"2"`, want: http.StatusPreconditionFailed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/tasks/t1", strings.NewReader(`{"title":"nuevo"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tc.want {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tc.want, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreferencesHandler expone las preferencias personales de cada usuario.
+type PreferencesHandler struct {
+	svc *service.PreferencesService
+}
+
+// NewPreferencesHandler construye una instancia del controlador HTTP.
+func NewPreferencesHandler(svc *service.PreferencesService) *PreferencesHandler {
+	return &PreferencesHandler{svc: svc}
+}
+
+// RegisterRoutes registra los endpoints de preferencias.
+func (h *PreferencesHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/users/:id/preferences", h.getPreferences)
+	rg.PUT("/users/:id/preferences", h.putPreferences)
+}
+
+// focusGoalRequest define un objetivo de foco.
+type focusGoalRequest struct {
+	Metric string `json:"metric" binding:"required,oneof=POMODOROS MINUTES"`
+	Target int    `json:"target" binding:"required,min=1,max=1440"`
+}
+
+// toDomain convierte el objetivo; un objetivo ausente queda en nil.
+func (r *focusGoalRequest) toDomain() *domain.FocusGoal {
+	if r == nil {
+		return nil
+	}
+	return &domain.FocusGoal{Metric: domain.GoalMetric(r.Metric), Target: r.Target}
+}
+
+// preferencesRequest reemplaza por completo las preferencias del usuario:
+// los campos opcionales omitidos (cadencia, objetivos) quedan sin configurar.
+type preferencesRequest struct {
+	Timezone string `json:"timezone" binding:"required"`
+	Locale   string `json:"locale" binding:"required,max=35"`
+
+	DefaultFocusMinutes int `json:"default_focus_minutes" binding:"required,min=1,max=120"`
+	DefaultBreakMinutes int `json:"default_break_minutes" binding:"min=0,max=60"`
+
+	Cadence        *cadenceRequest   `json:"cadence"`
+	AutoStartBreak bool              `json:"auto_start_break"`
+	DailyGoal      *focusGoalRequest `json:"daily_goal"`
+	WeeklyGoal     *focusGoalRequest `json:"weekly_goal"`
+	WeekStartDay   int               `json:"week_start_day" binding:"min=0,max=6"`
+}
+
+// getPreferences devuelve las preferencias del usuario (o las por defecto).
+func (h *PreferencesHandler) getPreferences(c *gin.Context) {
+	prefs, err := h.svc.GetPreferences(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo preferencias"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// putPreferences crea o reemplaza las preferencias del usuario.
+func (h *PreferencesHandler) putPreferences(c *gin.Context) {
+	var req preferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	prefs := &domain.UserPreferences{
+		UserID:              c.Param("id"),
+		Timezone:            req.Timezone,
+		Locale:              req.Locale,
+		DefaultFocusMinutes: req.DefaultFocusMinutes,
+		DefaultBreakMinutes: req.DefaultBreakMinutes,
+		AutoStartBreak:      req.AutoStartBreak,
+		WeekStartDay:        time.Weekday(req.WeekStartDay),
+	}
+	if req.Cadence != nil {
+		prefs.Cadence = &domain.BreakCadence{
+			ShortBreakMinutes: req.Cadence.ShortBreakMinutes,
+			LongBreakMinutes:  req.Cadence.LongBreakMinutes,
+			PomodorosPerSet:   req.Cadence.PomodorosPerSet,
+		}
+	}
+	prefs.DailyGoal = req.DailyGoal.toDomain()
+	prefs.WeeklyGoal = req.WeeklyGoal.toDomain()
+
+	saved, err := h.svc.UpdatePreferences(prefs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimezone) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "zona horaria inválida"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudieron guardar las preferencias"})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
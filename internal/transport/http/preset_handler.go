@@ -0,0 +1,141 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PresetHandler expone el CRUD de presets de sesión.
+type PresetHandler struct {
+	svc *service.PresetService
+}
+
+// NewPresetHandler construye una instancia del controlador HTTP.
+func NewPresetHandler(svc *service.PresetService) *PresetHandler {
+	return &PresetHandler{svc: svc}
+}
+
+// RegisterRoutes registra todos los endpoints relacionados con presets.
+func (h *PresetHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	presets := rg.Group("/presets")
+	{
+		presets.POST("", h.createPreset)
+		presets.GET("/user/:userID", h.getPresetsByUser)
+		presets.GET("/:id", h.getPreset)
+		presets.PUT("/:id", h.updatePreset)
+		presets.DELETE("/:id", h.deletePreset)
+	}
+}
+
+// presetRequest define los campos editables de un preset; los límites
+// son los mismos que acepta POST /sessions.
+type presetRequest struct {
+	Name         string `json:"name" binding:"required,max=60"`
+	FocusMinutes int    `json:"focus_minutes" binding:"required,min=1,max=120"`
+	BreakMinutes int    `json:"break_minutes" binding:"min=0,max=60"`
+	IsDefault    bool   `json:"is_default"`
+}
+
+// createPresetRequest añade el propietario al cuerpo de creación.
+type createPresetRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	presetRequest
+}
+
+// createPreset maneja la creación de un nuevo preset.
+func (h *PresetHandler) createPreset(c *gin.Context) {
+	var req createPresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preset, err := h.svc.CreatePreset(
+		req.UserID,
+		strings.TrimSpace(req.Name),
+		req.FocusMinutes,
+		req.BreakMinutes,
+		req.IsDefault,
+	)
+	if err != nil {
+		writePresetError(c, err, "error al crear el preset")
+		return
+	}
+
+	c.JSON(http.StatusCreated, preset)
+}
+
+// getPresetsByUser devuelve los presets de un usuario.
+func (h *PresetHandler) getPresetsByUser(c *gin.Context) {
+	presets, err := h.svc.GetPresetsByUser(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo presets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+// getPreset devuelve un preset por ID.
+func (h *PresetHandler) getPreset(c *gin.Context) {
+	preset, err := h.svc.GetPreset(c.Param("id"))
+	if err != nil {
+		writePresetError(c, err, "error obteniendo el preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// updatePreset reemplaza los campos editables de un preset.
+func (h *PresetHandler) updatePreset(c *gin.Context) {
+	var req presetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preset, err := h.svc.UpdatePreset(
+		c.Param("id"),
+		strings.TrimSpace(req.Name),
+		req.FocusMinutes,
+		req.BreakMinutes,
+		req.IsDefault,
+	)
+	if err != nil {
+		writePresetError(c, err, "error al actualizar el preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// deletePreset elimina un preset por ID.
+func (h *PresetHandler) deletePreset(c *gin.Context) {
+	if err := h.svc.DeletePreset(c.Param("id")); err != nil {
+		writePresetError(c, err, "error al eliminar el preset")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// writePresetError traduce los errores del PresetService: preset
+// inexistente → 404, nombre repetido → 409; el resto → 500 con failMessage.
+func writePresetError(c *gin.Context, err error, failMessage string) {
+	switch {
+	case errors.Is(err, service.ErrPresetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "preset no encontrado"})
+	case errors.Is(err, service.ErrPresetNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "ya existe un preset con ese nombre"})
+	case errors.Is(err, service.ErrDefaultPresetTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "otro preset pasó a ser el predeterminado; vuelve a intentarlo"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMessage})
+	}
+}
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"pomodoro-backend/internal/domain"
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler expone los informes de productividad.
+type ReportHandler struct {
+	svc *service.ReportService
+}
+
+// NewReportHandler construye una instancia del controlador HTTP.
+func NewReportHandler(svc *service.ReportService) *ReportHandler {
+	return &ReportHandler{svc: svc}
+}
+
+// RegisterRoutes registra los endpoints de informes.
+func (h *ReportHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/users/:id/reports", h.getReport)
+	rg.GET("/users/:id/reports/heatmap", h.getHeatmap)
+	rg.GET("/users/:id/reports/estimates", h.getEstimateAccuracy)
+}
+
+// getReport devuelve el informe del periodo indicado (?period=day|week|month,
+// por defecto week). ?at (RFC3339) permite consultar un periodo pasado y
+// ?tag lo limita a las tareas con esa etiqueta.
+func (h *ReportHandler) getReport(c *gin.Context) {
+	period := domain.ReportPeriod(c.DefaultQuery("period", string(domain.ReportPeriodWeek)))
+
+	at, err := parseTimeQuery(c, "at")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro at inválido", "detail": err.Error()})
+		return
+	}
+	if at == nil {
+		now := time.Now()
+		at = &now
+	}
+
+	report, err := h.svc.GetReport(c.Param("id"), period, *at, c.Query("tag"))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "periodo inválido, se espera day, week o month"})
+			return
+		}
+		if errors.Is(err, service.ErrTagNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "etiqueta no encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error generando el informe"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// getHeatmap devuelve la matriz 7x24 de minutos de foco del usuario en el
+// rango ?from/?to (RFC3339; por defecto, los últimos 30 días).
+func (h *ReportHandler) getHeatmap(c *gin.Context) {
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro from inválido", "detail": err.Error()})
+		return
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro to inválido", "detail": err.Error()})
+		return
+	}
+
+	heatmap, err := h.svc.GetFocusHeatmap(c.Param("id"), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rango inválido, máximo un año"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error generando el mapa de calor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// estimateAccuracyQuery define los parámetros de GET /reports/estimates.
+type estimateAccuracyQuery struct {
+	Weeks int `form:"weeks" binding:"omitempty,min=1,max=52"`
+}
+
+// getEstimateAccuracy devuelve la precisión de las estimaciones de tareas
+// del usuario en las últimas ?weeks semanas (por defecto, 12).
+func (h *ReportHandler) getEstimateAccuracy(c *gin.Context) {
+	var q estimateAccuracyQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetros inválidos", "detail": err.Error()})
+		return
+	}
+
+	accuracy, err := h.svc.GetEstimateAccuracy(c.Param("id"), q.Weeks)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReportRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rango inválido, entre 1 y 52 semanas"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error generando el informe de estimaciones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, accuracy)
+}
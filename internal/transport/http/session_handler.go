@@ -1,8 +1,12 @@
 package http
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 
+	"pomodoro-backend/internal/domain"
 	"pomodoro-backend/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -27,20 +31,48 @@ func (h *SessionHandler) RegisterRoutes(rg *gin.RouterGroup) {
 	sessions := rg.Group("/sessions")
 	{
 		sessions.POST("", h.createSession)
+		sessions.GET("", h.listSessions)
+		sessions.GET("/:id/events", h.getSessionEvents)
+		sessions.GET("/:id/transitions", h.getSessionTransitions)
 		sessions.PATCH("/:id/pause", h.pauseSession)
 		sessions.PATCH("/:id/resume", h.resumeSession)
 		sessions.PATCH("/:id/finish", h.finishSession)
+		sessions.PATCH("/:id/cancel", h.cancelSession)
+		sessions.PATCH("/:id/extend", h.extendPhase)
+		sessions.PATCH("/:id/shorten", h.shortenPhase)
+
+		sessions.PATCH("/:id/break/start", h.startBreak)
+		sessions.PATCH("/:id/break/pause", h.pauseBreak)
+		sessions.PATCH("/:id/break/resume", h.resumeBreak)
+		sessions.PATCH("/:id/break/finish", h.finishBreak)
+		sessions.PATCH("/:id/break/skip", h.skipBreak)
 	}
+
+	rg.GET("/users/:id/sessions/active", h.getActiveSession)
+
+	// Cadencia de descansos del usuario
+	rg.GET("/users/:id/cadence", h.getCadence)
+	rg.PUT("/users/:id/cadence", h.putCadence)
+
+	// Analítica de interrupciones
+	rg.GET("/users/:id/interruptions", h.getUserInterruptions)
+	rg.GET("/tasks/:id/interruptions", h.getTaskInterruptions)
 }
 
 // createSessionRequest define el cuerpo esperado para la creación
-// de una nueva sesión Pomodoro.
+// de una nueva sesión Pomodoro. Las duraciones omitidas se toman del
+// preset indicado o del predeterminado del usuario.
 type createSessionRequest struct {
 	UserID       string  `json:"user_id" binding:"required"`
 	ProjectID    *string `json:"project_id"`
 	TaskID       *string `json:"task_id"`
-	FocusMinutes int     `json:"focus_minutes" binding:"required,min=1,max=120"`
-	BreakMinutes int     `json:"break_minutes" binding:"required,min=0,max=60"`
+	PresetID     *string `json:"preset_id"`
+	FocusMinutes *int    `json:"focus_minutes" binding:"omitempty,min=1,max=120"`
+	BreakMinutes *int    `json:"break_minutes" binding:"omitempty,min=0,max=60"`
+
+	// Si el usuario ya tiene una sesión activa, cancelarla en lugar de
+	// rechazar la petición con 409.
+	ReplaceActive bool `json:"replace_active"`
 }
 
 // createSession maneja la creación de una nueva sesión Pomodoro.
@@ -54,68 +86,416 @@ func (h *SessionHandler) createSession(c *gin.Context) {
 		return
 	}
 
+	focusMin, breakMin, err := h.svc.ResolveDurations(req.UserID, req.PresetID, req.FocusMinutes, req.BreakMinutes)
+	if err != nil {
+		if errors.Is(err, service.ErrPresetNotFound) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "preset no encontrado"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo crear la sesión"})
+		return
+	}
+
 	session, err := h.svc.CreateAndStartSession(
 		req.UserID,
 		req.ProjectID,
 		req.TaskID,
-		req.FocusMinutes,
-		req.BreakMinutes,
+		focusMin,
+		breakMin,
+		req.ReplaceActive,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrActiveSessionExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "el usuario ya tiene una sesión activa"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo crear la sesión"})
 		return
 	}
 
+	setETag(c, session.Version)
 	c.JSON(http.StatusCreated, session)
 }
 
-// pauseSession cambia el estado de la sesión a PAUSED.
-func (h *SessionHandler) pauseSession(c *gin.Context) {
-	id := c.Param("id")
+// listSessionsQuery define los filtros y la paginación de GET /sessions.
+type listSessionsQuery struct {
+	UserID    string `form:"user_id"`
+	TaskID    string `form:"task_id"`
+	ProjectID string `form:"project_id"`
+	State     string `form:"state"`
+	Cursor    string `form:"cursor"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Sort      string `form:"sort" binding:"omitempty,oneof=started_at -started_at"`
+}
+
+// listSessions devuelve el historial de sesiones filtrado y paginado por
+// cursor. Admite ?from= y ?to= (RFC3339) sobre started_at y ?sort=started_at
+// para orden ascendente (por defecto, -started_at).
+func (h *SessionHandler) listSessions(c *gin.Context) {
+	var q listSessionsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetros inválidos", "detail": err.Error()})
+		return
+	}
+
+	state := domain.SessionState(q.State)
+	if state != "" && !state.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "estado inválido"})
+		return
+	}
+
+	query := domain.SessionQuery{
+		UserID:    q.UserID,
+		TaskID:    q.TaskID,
+		ProjectID: q.ProjectID,
+		State:     state,
+		Cursor:    q.Cursor,
+		Limit:     q.Limit,
+		Ascending: q.Sort == "started_at",
+	}
+
+	var err error
+	if query.From, err = parseTimeQuery(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro from inválido", "detail": err.Error()})
+		return
+	}
+	if query.To, err = parseTimeQuery(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro to inválido", "detail": err.Error()})
+		return
+	}
 
-	session, err := h.svc.PauseSession(id)
+	page, err := h.svc.ListSessions(query)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == service.ErrInvalidStateTransition {
-			status = http.StatusBadRequest
+		if errors.Is(err, service.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "cursor inválido"})
+			return
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo sesiones"})
 		return
 	}
 
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, page)
 }
 
-// resumeSession cambia el estado de la sesión a RUNNING.
-func (h *SessionHandler) resumeSession(c *gin.Context) {
-	id := c.Param("id")
-
-	session, err := h.svc.ResumeSession(id)
+// getActiveSession devuelve la sesión activa del usuario o 404 si no tiene.
+func (h *SessionHandler) getActiveSession(c *gin.Context) {
+	session, err := h.svc.GetActiveSession(c.Param("id"))
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == service.ErrInvalidStateTransition {
-			status = http.StatusBadRequest
+		if errors.Is(err, service.ErrNoActiveSession) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "el usuario no tiene una sesión activa"})
+			return
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo la sesión activa"})
 		return
 	}
 
+	setETag(c, session.Version)
 	c.JSON(http.StatusOK, session)
 }
 
+// pauseSessionRequest es el cuerpo opcional de la pausa: clasifica la
+// interrupción (INTERNAL/EXTERNAL) y admite un motivo libre.
+type pauseSessionRequest struct {
+	Type   string `json:"type" binding:"omitempty,oneof=INTERNAL EXTERNAL"`
+	Reason string `json:"reason" binding:"max=280"`
+}
+
+// pauseSession cambia el estado de la sesión a PAUSED.
+func (h *SessionHandler) pauseSession(c *gin.Context) {
+	var req pauseSessionRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	h.applyTransition(c, func(id string, version int64) (*domain.Session, error) {
+		return h.svc.PauseSession(id, version, domain.Interruption{
+			Type:   domain.InterruptionType(req.Type),
+			Reason: strings.TrimSpace(req.Reason),
+		})
+	})
+}
+
+// resumeSession cambia el estado de la sesión a RUNNING.
+func (h *SessionHandler) resumeSession(c *gin.Context) {
+	h.applyTransition(c, h.svc.ResumeSession)
+}
+
 // finishSession marca la sesión como finalizada.
 func (h *SessionHandler) finishSession(c *gin.Context) {
-	id := c.Param("id")
+	h.applyTransition(c, h.svc.FinishSession)
+}
+
+// reasonRequest es el cuerpo opcional de las transiciones que solo admiten
+// un motivo libre (cancelación).
+type reasonRequest struct {
+	Reason string `json:"reason" binding:"max=280"`
+}
+
+// cancelSession abandona la sesión, sea en focus o en break.
+func (h *SessionHandler) cancelSession(c *gin.Context) {
+	var req reasonRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	h.applyTransition(c, func(id string, version int64) (*domain.Session, error) {
+		return h.svc.CancelSession(id, version, strings.TrimSpace(req.Reason))
+	})
+}
+
+// adjustPhaseRequest indica cuántos minutos sumar o restar a la fase.
+type adjustPhaseRequest struct {
+	Minutes int `json:"minutes" binding:"required,min=1,max=120"`
+}
+
+// extendPhase alarga el focus o el break en curso.
+func (h *SessionHandler) extendPhase(c *gin.Context) {
+	h.adjustPhase(c, h.svc.ExtendPhase)
+}
+
+// shortenPhase acorta el focus o el break en curso.
+func (h *SessionHandler) shortenPhase(c *gin.Context) {
+	h.adjustPhase(c, h.svc.ShortenPhase)
+}
+
+// adjustPhase valida el cuerpo común de extend/shorten y delega en el
+// servicio correspondiente.
+func (h *SessionHandler) adjustPhase(c *gin.Context, adjust func(id string, version int64, minutes int) (*domain.Session, error)) {
+	var req adjustPhaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	h.applyTransition(c, func(id string, version int64) (*domain.Session, error) {
+		return adjust(id, version, req.Minutes)
+	})
+}
+
+// getSessionEvents devuelve la línea de tiempo de la sesión.
+func (h *SessionHandler) getSessionEvents(c *gin.Context) {
+	events, err := h.svc.GetSessionEvents(c.Param("id"))
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// getSessionTransitions lista las acciones válidas desde el estado actual
+// de la sesión, para que el cliente no tenga que replicar las reglas.
+func (h *SessionHandler) getSessionTransitions(c *gin.Context) {
+	available, err := h.svc.GetAvailableTransitions(c.Param("id"))
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	setETag(c, available.Version)
+	c.JSON(http.StatusOK, available)
+}
+
+// startBreak inicia el descanso de una sesión cuyo focus ya terminó.
+func (h *SessionHandler) startBreak(c *gin.Context) {
+	h.applyTransition(c, h.svc.StartBreak)
+}
+
+// pauseBreak pausa el descanso en curso.
+func (h *SessionHandler) pauseBreak(c *gin.Context) {
+	h.applyTransition(c, h.svc.PauseBreak)
+}
+
+// resumeBreak reanuda un descanso pausado.
+func (h *SessionHandler) resumeBreak(c *gin.Context) {
+	h.applyTransition(c, h.svc.ResumeBreak)
+}
+
+// finishBreak marca el descanso como finalizado.
+func (h *SessionHandler) finishBreak(c *gin.Context) {
+	h.applyTransition(c, h.svc.FinishBreak)
+}
+
+// skipBreakRequest es el cuerpo opcional de break/skip.
+type skipBreakRequest struct {
+	StartNext bool `json:"start_next"`
+}
+
+// skipBreak salta el descanso y, si se pide, inicia el siguiente pomodoro.
+// La respuesta incluye la sesión cerrada y, en su caso, la nueva; el ETag
+// corresponde a la sesión cerrada. Si el salto se persistió pero el nuevo
+// pomodoro no pudo crearse, responde 200 con next_session nulo y el motivo
+// en next_session_error.
+func (h *SessionHandler) skipBreak(c *gin.Context) {
+	var req skipBreakRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
 
-	session, err := h.svc.FinishSession(id)
+	version, err := parseIfMatch(c)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == service.ErrInvalidStateTransition {
-			status = http.StatusBadRequest
+		writeIfMatchError(c, err)
+		return
+	}
+
+	skipped, next, err := h.svc.SkipBreak(c.Param("id"), version, req.StartNext)
+	if skipped == nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	body := gin.H{
+		"session":      skipped,
+		"next_session": next,
+	}
+	if err != nil {
+		body["next_session_error"] = err.Error()
+	}
+
+	setETag(c, skipped.Version)
+	c.JSON(http.StatusOK, body)
+}
+
+// cadenceRequest define la cadencia de descansos configurable por usuario.
+type cadenceRequest struct {
+	ShortBreakMinutes int `json:"short_break_minutes" binding:"required,min=1,max=60"`
+	LongBreakMinutes  int `json:"long_break_minutes" binding:"required,min=1,max=120"`
+	PomodorosPerSet   int `json:"pomodoros_per_set" binding:"required,min=1,max=12"`
+}
+
+// getCadence devuelve la cadencia de descansos del usuario.
+func (h *SessionHandler) getCadence(c *gin.Context) {
+	cadence, err := h.svc.GetBreakCadence(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrCadenceNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "cadencia no configurada"})
+			return
 		}
-		c.JSON(status, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo la cadencia"})
 		return
 	}
 
+	c.JSON(http.StatusOK, cadence)
+}
+
+// putCadence crea o reemplaza la cadencia de descansos del usuario.
+func (h *SessionHandler) putCadence(c *gin.Context) {
+	var req cadenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "payload inválido",
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	cadence, err := h.svc.SetBreakCadence(c.Param("id"), &domain.BreakCadence{
+		ShortBreakMinutes: req.ShortBreakMinutes,
+		LongBreakMinutes:  req.LongBreakMinutes,
+		PomodorosPerSet:   req.PomodorosPerSet,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo guardar la cadencia"})
+		return
+	}
+
+	c.JSON(http.StatusOK, cadence)
+}
+
+// getUserInterruptions agrega las interrupciones de un usuario.
+func (h *SessionHandler) getUserInterruptions(c *gin.Context) {
+	h.writeInterruptionStats(c, domain.InterruptionFilter{UserID: c.Param("id")})
+}
+
+// getTaskInterruptions agrega las interrupciones de las sesiones de una tarea.
+func (h *SessionHandler) getTaskInterruptions(c *gin.Context) {
+	h.writeInterruptionStats(c, domain.InterruptionFilter{TaskID: c.Param("id")})
+}
+
+// writeInterruptionStats completa el filtro con el rango ?from=&to= y
+// responde con la analítica agregada.
+func (h *SessionHandler) writeInterruptionStats(c *gin.Context, filter domain.InterruptionFilter) {
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro from inválido", "detail": err.Error()})
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetro to inválido", "detail": err.Error()})
+		return
+	}
+
+	stats, err := h.svc.GetInterruptionStats(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo interrupciones"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// applyTransition ejecuta una transición de estado sobre la sesión :id,
+// respetando la precondición If-Match, y responde con la sesión resultante
+// y su nuevo ETag.
+func (h *SessionHandler) applyTransition(c *gin.Context, transition func(id string, version int64) (*domain.Session, error)) {
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	session, err := transition(c.Param("id"), version)
+	if err != nil {
+		writeSessionError(c, err)
+		return
+	}
+
+	setETag(c, session.Version)
 	c.JSON(http.StatusOK, session)
 }
+
+// bindOptionalJSON decodifica el cuerpo JSON si existe; un cuerpo vacío no
+// se considera error.
+func bindOptionalJSON(c *gin.Context, obj any) error {
+	if err := c.ShouldBindJSON(obj); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// writeSessionError traduce los errores del SessionService a códigos HTTP:
+// sesión inexistente → 404, transición no permitida → 409, conflicto de
+// versión → 412/409 (ver versionConflictStatus).
+func writeSessionError(c *gin.Context, err error) {
+	if status, ok := versionConflictStatus(c, err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, service.ErrInvalidStateTransition),
+		errors.Is(err, service.ErrActiveSessionExists):
+		status = http.StatusConflict
+	case errors.Is(err, service.ErrInvalidPhaseAdjustment):
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreakHandler expone las rachas de constancia de cada usuario.
+type StreakHandler struct {
+	svc *service.StreakService
+}
+
+// NewStreakHandler construye una instancia del controlador HTTP.
+func NewStreakHandler(svc *service.StreakService) *StreakHandler {
+	return &StreakHandler{svc: svc}
+}
+
+// RegisterRoutes registra los endpoints de rachas.
+func (h *StreakHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	rg.GET("/users/:id/streak", h.getStreak)
+}
+
+// getStreak devuelve la racha actual y la más larga del usuario.
+func (h *StreakHandler) getStreak(c *gin.Context) {
+	streak, err := h.svc.GetStreak(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo la racha"})
+		return
+	}
+
+	c.JSON(http.StatusOK, streak)
+}
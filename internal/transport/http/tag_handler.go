@@ -0,0 +1,125 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"pomodoro-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler expone el CRUD de etiquetas de tareas.
+type TagHandler struct {
+	svc *service.TagService
+}
+
+// NewTagHandler construye una instancia del controlador HTTP.
+func NewTagHandler(svc *service.TagService) *TagHandler {
+	return &TagHandler{svc: svc}
+}
+
+// RegisterRoutes registra todos los endpoints relacionados con etiquetas.
+func (h *TagHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	tags := rg.Group("/tags")
+	{
+		tags.POST("", h.createTag)
+		tags.GET("/user/:userID", h.getTagsByUser)
+		tags.GET("/:id", h.getTag)
+		tags.PUT("/:id", h.updateTag)
+		tags.DELETE("/:id", h.deleteTag)
+	}
+}
+
+// tagRequest define los campos editables de una etiqueta. Sin color se
+// usa el gris por defecto.
+type tagRequest struct {
+	Name  string `json:"name" binding:"required,max=40"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+// createTagRequest añade el propietario al cuerpo de creación.
+type createTagRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	tagRequest
+}
+
+// createTag maneja la creación de una nueva etiqueta.
+func (h *TagHandler) createTag(c *gin.Context) {
+	var req createTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.svc.CreateTag(req.UserID, strings.TrimSpace(req.Name), req.Color)
+	if err != nil {
+		writeTagError(c, err, "error al crear la etiqueta")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// getTagsByUser devuelve las etiquetas de un usuario.
+func (h *TagHandler) getTagsByUser(c *gin.Context) {
+	tags, err := h.svc.GetTagsByUser(c.Param("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo etiquetas"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+// getTag devuelve una etiqueta por ID.
+func (h *TagHandler) getTag(c *gin.Context) {
+	tag, err := h.svc.GetTag(c.Param("id"))
+	if err != nil {
+		writeTagError(c, err, "error obteniendo la etiqueta")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// updateTag reemplaza el nombre y el color de una etiqueta.
+func (h *TagHandler) updateTag(c *gin.Context) {
+	var req tagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tag, err := h.svc.UpdateTag(c.Param("id"), strings.TrimSpace(req.Name), req.Color)
+	if err != nil {
+		writeTagError(c, err, "error al actualizar la etiqueta")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// deleteTag elimina una etiqueta y la quita de sus tareas.
+func (h *TagHandler) deleteTag(c *gin.Context) {
+	if err := h.svc.DeleteTag(c.Param("id")); err != nil {
+		writeTagError(c, err, "error al eliminar la etiqueta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// writeTagError traduce los errores del TagService: etiqueta inexistente
+// → 404, nombre repetido → 409; el resto → 500 con failMessage.
+func writeTagError(c *gin.Context, err error, failMessage string) {
+	switch {
+	case errors.Is(err, service.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "etiqueta no encontrada"})
+	case errors.Is(err, service.ErrTagNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "ya existe una etiqueta con ese nombre"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": failMessage})
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"pomodoro-backend/internal/domain"
@@ -38,6 +39,16 @@ func (h *TaskHandler) RegisterRoutes(rg *gin.RouterGroup) {
 		tasks.PATCH("/:id/start", h.markInProgress)
 		tasks.PATCH("/:id/pause", h.markPaused)
 		tasks.PATCH("/:id/reopen", h.reopenTask)
+
+		tasks.GET("/:id/children", h.getChildren)
+		tasks.PATCH("/:id/parent", h.moveTask)
+
+		tasks.PUT("/:id/tags", h.setTags)
+
+		tasks.POST("/:id/checklist", h.addChecklistItem)
+		tasks.PUT("/:id/checklist/order", h.reorderChecklist)
+		tasks.PATCH("/:id/checklist/:itemID/toggle", h.toggleChecklistItem)
+		tasks.DELETE("/:id/checklist/:itemID", h.removeChecklistItem)
 	}
 }
 
@@ -45,67 +56,66 @@ func (h *TaskHandler) RegisterRoutes(rg *gin.RouterGroup) {
 //
 // Estructura utilizada para validar el cuerpo de la petición POST.
 type createTaskRequest struct {
-	UserID      string  `json:"user_id" binding:"required"`
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description"`
-	ProjectID   *string `json:"project_id"`
+	UserID             string  `json:"user_id" binding:"required"`
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	ProjectID          *string `json:"project_id"`
+	ParentID           *string `json:"parent_id"`
+	EstimatedPomodoros *int    `json:"estimated_pomodoros" binding:"omitempty,min=1,max=100"`
+	AutoComplete       bool    `json:"auto_complete"`
 }
 
 func (h *TaskHandler) markCompleted(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.svc.MarkCompleted(id)
+	version, err := parseIfMatch(c)
 	if err != nil {
-		if err == service.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "tarea no encontrada"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo completar la tarea"})
+		writeIfMatchError(c, err)
+		return
+	}
+
+	err = h.svc.MarkCompleted(id, version)
+	if err != nil {
+		writeTaskError(c, err, "no se pudo completar la tarea")
 		return
 	}
 
 	// Opcional: devolver la tarea actualizada
 	updated, _ := h.svc.GetTask(id)
-	c.JSON(http.StatusOK, updated)
+	writeTask(c, http.StatusOK, updated)
 }
 
 func (h *TaskHandler) markInProgress(c *gin.Context) {
-	id := c.Param("id")
+	h.updateStatus(c, domain.TaskStatusInProgress, "no se pudo actualizar el estado")
+}
 
-	err := h.svc.UpdateStatus(id, domain.TaskStatusInProgress)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo actualizar el estado"})
-		return
-	}
+func (h *TaskHandler) markPaused(c *gin.Context) {
+	h.updateStatus(c, domain.TaskStatusPaused, "no se pudo pausar la tarea")
+}
 
-	updated, _ := h.svc.GetTask(id)
-	c.JSON(http.StatusOK, updated)
+func (h *TaskHandler) reopenTask(c *gin.Context) {
+	h.updateStatus(c, domain.TaskStatusPending, "no se pudo reabrir la tarea")
 }
 
-func (h *TaskHandler) markPaused(c *gin.Context) {
+// updateStatus aplica el cambio de estado respetando la precondición
+// If-Match y devuelve la tarea actualizada.
+func (h *TaskHandler) updateStatus(c *gin.Context, status domain.TaskStatus, failMessage string) {
 	id := c.Param("id")
 
-	err := h.svc.UpdateStatus(id, domain.TaskStatusPaused)
+	version, err := parseIfMatch(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo pausar la tarea"})
+		writeIfMatchError(c, err)
 		return
 	}
 
-	updated, _ := h.svc.GetTask(id)
-	c.JSON(http.StatusOK, updated)
-}
-
-func (h *TaskHandler) reopenTask(c *gin.Context) {
-	id := c.Param("id")
-
-	err := h.svc.UpdateStatus(id, domain.TaskStatusPending)
+	err = h.svc.UpdateStatus(id, version, status)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "no se pudo reabrir la tarea"})
+		writeTaskError(c, err, failMessage)
 		return
 	}
 
 	updated, _ := h.svc.GetTask(id)
-	c.JSON(http.StatusOK, updated)
+	writeTask(c, http.StatusOK, updated)
 }
 
 // createTask maneja la creación de una nueva tarea.
@@ -116,20 +126,22 @@ func (h *TaskHandler) createTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.svc.CreateTask(req.UserID, req.Title, req.Description, req.ProjectID)
+	task, err := h.svc.CreateTask(req.UserID, req.Title, req.Description, req.ProjectID, req.ParentID, req.EstimatedPomodoros, req.AutoComplete)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error al crear la tarea"})
+		writeTaskError(c, err, "error al crear la tarea")
 		return
 	}
 
-	c.JSON(http.StatusCreated, task)
+	writeTask(c, http.StatusCreated, task)
 }
 
 // getTasksByUser devuelve todas las tareas pertenecientes a un usuario.
+// ?tag puede repetirse para quedarse con las tareas que tienen todas las
+// etiquetas indicadas.
 func (h *TaskHandler) getTasksByUser(c *gin.Context) {
 	userID := c.Param("userID")
 
-	tasks, err := h.svc.GetTasksByUser(userID)
+	tasks, err := h.svc.GetTasksByUser(userID, c.QueryArray("tag"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "error obteniendo tareas"})
 		return
@@ -148,14 +160,16 @@ func (h *TaskHandler) getTask(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	writeTask(c, http.StatusOK, task)
 }
 
 // updateTask actualiza los datos de una tarea.
 type updateTaskRequest struct {
-	Title       string  `json:"title" binding:"required"`
-	Description string  `json:"description"`
-	ProjectID   *string `json:"project_id"`
+	Title              string  `json:"title" binding:"required"`
+	Description        string  `json:"description"`
+	ProjectID          *string `json:"project_id"`
+	EstimatedPomodoros *int    `json:"estimated_pomodoros" binding:"omitempty,min=1,max=100"`
+	AutoComplete       bool    `json:"auto_complete"`
 }
 
 func (h *TaskHandler) updateTask(c *gin.Context) {
@@ -167,23 +181,239 @@ func (h *TaskHandler) updateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.svc.UpdateTask(id, req.Title, req.Description, req.ProjectID)
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.UpdateTask(id, version, req.Title, req.Description, req.ProjectID, req.EstimatedPomodoros, req.AutoComplete)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error al actualizar tarea"})
+		writeTaskError(c, err, "error al actualizar tarea")
 		return
 	}
 
-	c.JSON(http.StatusOK, task)
+	writeTask(c, http.StatusOK, task)
 }
 
-// deleteTask elimina una tarea por ID.
+// deleteTaskQuery define los parámetros de DELETE /tasks/:id.
+type deleteTaskQuery struct {
+	Cascade bool `form:"cascade"`
+}
+
+// deleteTask elimina una tarea por ID. Una tarea con subtareas solo se
+// elimina con ?cascade=true, que borra el subárbol completo.
 func (h *TaskHandler) deleteTask(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.svc.DeleteTask(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "error al eliminar tarea"})
+	var q deleteTaskQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "parámetros inválidos", "detail": err.Error()})
+		return
+	}
+
+	if err := h.svc.DeleteTask(id, q.Cascade); err != nil {
+		writeTaskError(c, err, "error al eliminar tarea")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"deleted": true})
 }
+
+// moveTaskRequest valida el cuerpo de PATCH /tasks/:id/parent; parent_id
+// null lleva la tarea a la raíz.
+type moveTaskRequest struct {
+	ParentID *string `json:"parent_id"`
+}
+
+// getChildren devuelve las subtareas directas de una tarea.
+func (h *TaskHandler) getChildren(c *gin.Context) {
+	children, err := h.svc.GetChildren(c.Param("id"))
+	if err != nil {
+		writeTaskError(c, err, "error obteniendo subtareas")
+		return
+	}
+
+	c.JSON(http.StatusOK, children)
+}
+
+// moveTask cambia el padre de la tarea; sus subtareas se mueven con ella.
+func (h *TaskHandler) moveTask(c *gin.Context) {
+	var req moveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payload inválido", "detail": err.Error()})
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.MoveTask(c.Param("id"), version, req.ParentID)
+	if err != nil {
+		writeTaskError(c, err, "no se pudo mover la tarea")
+		return
+	}
+
+	writeTask(c, http.StatusOK, task)
+}
+
+// setTagsRequest valida el cuerpo de PUT /tasks/:id/tags.
+type setTagsRequest struct {
+	TagIDs []string `json:"tag_ids" binding:"required"`
+}
+
+// setTags reemplaza las etiquetas asignadas a la tarea.
+func (h *TaskHandler) setTags(c *gin.Context) {
+	var req setTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payload inválido", "detail": err.Error()})
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.SetTags(c.Param("id"), version, req.TagIDs)
+	if err != nil {
+		writeTaskError(c, err, "no se pudieron asignar las etiquetas")
+		return
+	}
+
+	writeTask(c, http.StatusOK, task)
+}
+
+// checklistItemRequest valida el cuerpo de POST /tasks/:id/checklist.
+type checklistItemRequest struct {
+	Title string `json:"title" binding:"required,max=200"`
+}
+
+// reorderChecklistRequest valida el cuerpo de PUT /tasks/:id/checklist/order.
+type reorderChecklistRequest struct {
+	ItemIDs []string `json:"item_ids" binding:"required"`
+}
+
+// addChecklistItem agrega un paso al final de la lista de la tarea.
+func (h *TaskHandler) addChecklistItem(c *gin.Context) {
+	var req checklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payload inválido", "detail": err.Error()})
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.AddChecklistItem(c.Param("id"), version, req.Title)
+	if err != nil {
+		writeTaskError(c, err, "no se pudo agregar el paso")
+		return
+	}
+
+	writeTask(c, http.StatusCreated, task)
+}
+
+// reorderChecklist reordena los pasos; item_ids debe incluirlos todos.
+func (h *TaskHandler) reorderChecklist(c *gin.Context) {
+	var req reorderChecklistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "payload inválido", "detail": err.Error()})
+		return
+	}
+
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.ReorderChecklist(c.Param("id"), version, req.ItemIDs)
+	if err != nil {
+		writeTaskError(c, err, "no se pudo reordenar la lista")
+		return
+	}
+
+	writeTask(c, http.StatusOK, task)
+}
+
+// toggleChecklistItem marca o desmarca un paso. Si la tarea tiene
+// auto_complete, completar el último paso la completa.
+func (h *TaskHandler) toggleChecklistItem(c *gin.Context) {
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.ToggleChecklistItem(c.Param("id"), version, c.Param("itemID"))
+	if err != nil {
+		writeTaskError(c, err, "no se pudo actualizar el paso")
+		return
+	}
+
+	writeTask(c, http.StatusOK, task)
+}
+
+// removeChecklistItem elimina un paso de la tarea. Con auto_complete, si
+// los pasos restantes están todos hechos, la tarea se completa.
+func (h *TaskHandler) removeChecklistItem(c *gin.Context) {
+	version, err := parseIfMatch(c)
+	if err != nil {
+		writeIfMatchError(c, err)
+		return
+	}
+
+	task, err := h.svc.RemoveChecklistItem(c.Param("id"), version, c.Param("itemID"))
+	if err != nil {
+		writeTaskError(c, err, "no se pudo eliminar el paso")
+		return
+	}
+
+	writeTask(c, http.StatusOK, task)
+}
+
+// writeTask responde con la tarea y publica su versión como ETag.
+func writeTask(c *gin.Context, status int, task *domain.Task) {
+	if task != nil {
+		setETag(c, task.Version)
+	}
+	c.JSON(status, task)
+}
+
+// writeTaskError traduce los errores del TaskService: tarea o paso
+// inexistente → 404, conflicto de versión → 412/409, tarea con subtareas →
+// 409, padre o etiquetas inválidos, ciclo, lista llena u orden inválido →
+// 422; el resto → 500 con failMessage.
+func writeTaskError(c *gin.Context, err error, failMessage string) {
+	if status, ok := versionConflictStatus(c, err); ok {
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "tarea no encontrada"})
+		return
+	case errors.Is(err, service.ErrChecklistItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "paso no encontrado"})
+		return
+	case errors.Is(err, service.ErrTaskHasChildren):
+		c.JSON(http.StatusConflict, gin.H{"error": "la tarea tiene subtareas, usa ?cascade=true para eliminarlas"})
+		return
+	case errors.Is(err, service.ErrParentTaskNotFound), errors.Is(err, service.ErrTaskCycle),
+		errors.Is(err, service.ErrTagNotFound), errors.Is(err, service.ErrTooManyTags),
+		errors.Is(err, service.ErrChecklistFull), errors.Is(err, service.ErrInvalidChecklistOrder):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": failMessage})
+}
@@ -2,10 +2,17 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os/signal"
+	"syscall"
 	"time"
 
+	// Base de zonas horarias embebida: la imagen alpine no la incluye y las
+	// preferencias del usuario usan zonas IANA.
+	_ "time/tzdata"
+
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -49,14 +56,64 @@ func main() {
 	sessionRepo := repository.NewMongoSessionRepository(db)
 	taskRepo := repository.NewMongoTaskRepository(db)
 	cycleRepo := repository.NewMongoCycleRepository(db)
+	prefsRepo := repository.NewMongoPreferencesRepository(db)
+	presetRepo := repository.NewMongoPresetRepository(db)
+	streakRepo := repository.NewMongoStreakRepository(db)
+	reportRepo := repository.NewMongoReportRepository(db)
+	tagRepo := repository.NewMongoTagRepository(db)
+
+	if err := cycleRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de ciclos: %v", err)
+	}
+	if err := presetRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de presets: %v", err)
+	}
+	if err := taskRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de tareas: %v", err)
+	}
+	if err := tagRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de etiquetas: %v", err)
+	}
+	if err := prefsRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de preferencias: %v", err)
+	}
 
 	// ---------------------------
 	// Inyección de Servicios
 	// ---------------------------
 
-	sessionService := service.NewSessionService(sessionRepo, taskRepo) // <- corregido
-	taskService := service.NewTaskService(taskRepo)
+	sessionService := service.NewSessionService(sessionRepo, taskRepo, cycleRepo, prefsRepo, presetRepo)
+	taskService := service.NewTaskService(taskRepo, tagRepo)
 	cycleService := service.NewCycleService(cycleRepo)
+	presetService := service.NewPresetService(presetRepo)
+	prefsService := service.NewPreferencesService(prefsRepo)
+	goalService := service.NewGoalService(sessionRepo, prefsRepo)
+	streakService := service.NewStreakService(streakRepo, sessionRepo, prefsRepo)
+	reportService := service.NewReportService(reportRepo, sessionRepo, taskRepo, tagRepo, prefsRepo)
+	tagService := service.NewTagService(tagRepo, taskRepo)
+
+	// El índice de sesión activa única no puede crearse mientras algún
+	// usuario conserve varias sesiones activas de antes de la restricción:
+	// se cancelan todas salvo la más reciente.
+	closed, err := sessionService.CloseDuplicateActiveSessions()
+	if err != nil {
+		log.Fatalf("error al cerrar sesiones activas duplicadas: %v", err)
+	}
+	if closed > 0 {
+		log.Printf("canceladas %d sesiones activas duplicadas", closed)
+	}
+	if err := sessionRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("error al crear índices de sesiones: %v", err)
+	}
+
+	// Servicios que reaccionan al ciclo de vida de las sesiones
+	streakService.RegisterHooks(sessionService)
+
+	// Reprogramar los deadlines de las sesiones que quedaron en curso
+	if err := sessionService.RecoverTimers(); err != nil {
+		log.Printf("no se pudieron recuperar los temporizadores: %v", err)
+	}
+	defer sessionService.StopTimers()
 
 	// ---------------------------
 	// Inyección de Handlers
@@ -64,7 +121,13 @@ func main() {
 
 	sessionHandler := httphandler.NewSessionHandler(sessionService)
 	taskHandler := httphandler.NewTaskHandler(taskService)
-	_ = cycleService // Pendiente: aún no tienes endpoints para cycles
+	cycleHandler := httphandler.NewCycleHandler(cycleService)
+	presetHandler := httphandler.NewPresetHandler(presetService)
+	prefsHandler := httphandler.NewPreferencesHandler(prefsService)
+	goalHandler := httphandler.NewGoalHandler(goalService)
+	streakHandler := httphandler.NewStreakHandler(streakService)
+	reportHandler := httphandler.NewReportHandler(reportService)
+	tagHandler := httphandler.NewTagHandler(tagService)
 
 	// ---------------------------
 	// Router
@@ -77,7 +140,8 @@ func main() {
 		// Permitir llamadas desde tu frontend en http://localhost:3000
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "http://localhost:3000")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 
 		if c.Request.Method == http.MethodOptions {
@@ -100,6 +164,13 @@ func main() {
 	{
 		sessionHandler.RegisterRoutes(api)
 		taskHandler.RegisterRoutes(api)
+		cycleHandler.RegisterRoutes(api)
+		presetHandler.RegisterRoutes(api)
+		prefsHandler.RegisterRoutes(api)
+		goalHandler.RegisterRoutes(api)
+		streakHandler.RegisterRoutes(api)
+		reportHandler.RegisterRoutes(api)
+		tagHandler.RegisterRoutes(api)
 	}
 
 	// ---------------------------
@@ -111,9 +182,27 @@ func main() {
 		Handler: router,
 	}
 
-	log.Printf("Pomodoro backend escuchando en puerto %s", cfg.HTTPPort)
+	// Al recibir SIGINT/SIGTERM se deja de aceptar tráfico, se esperan las
+	// peticiones en curso y main retorna, con lo que corren los defer
+	// (temporizadores de sesión incluidos).
+	stop, cancelSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancelSignals()
+
+	go func() {
+		log.Printf("Pomodoro backend escuchando en puerto %s", cfg.HTTPPort)
+
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("error al iniciar servidor: %v", err)
+		}
+	}()
+
+	<-stop.Done()
+	log.Printf("apagando servidor...")
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelShutdown()
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("error al iniciar servidor: %v", err)
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("error al apagar el servidor: %v", err)
 	}
 }